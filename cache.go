@@ -0,0 +1,178 @@
+package polygon
+
+import (
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Cache stores raw responses of read-only endpoints, grouped by problem.
+// Entries of methods which are not bound to a problem use an empty problemId.
+// Implementations must be safe for concurrent use, and must not share the bodies they store
+// with their callers, which may modify them.
+type Cache interface {
+	// Get returns the body stored under key, if it is present and not expired
+	Get(problemId, key string) (body []byte, ok bool)
+	// Set stores body under key for the duration ttl
+	Set(problemId, key string, body []byte, ttl time.Duration)
+	// Invalidate removes every entry of the problem
+	Invalidate(problemId string)
+}
+
+// DefaultCacheTTL is used by CacheInterceptor when no TTLs are given
+var DefaultCacheTTL = map[string]time.Duration{
+	problemsListEp: time.Minute,
+	problemInfoEp:  10 * time.Minute,
+	problemTestsEp: 10 * time.Minute,
+	problemFilesEp: 10 * time.Minute,
+}
+
+// CacheInterceptor returns an Interceptor which serves read-only calls from cache.
+//
+// ttl maps an API method name (e.g. "problem.info") to the lifetime of its entries,
+// methods absent from the map are never cached. If ttl is nil, DefaultCacheTTL is used.
+//
+// Entries of a problem are invalidated when any save endpoint is called on it through the same client,
+// or when problems.list reports that its revision changed.
+func CacheInterceptor(cache Cache, ttl map[string]time.Duration) Interceptor {
+	if ttl == nil {
+		ttl = DefaultCacheTTL
+	}
+
+	var mu sync.Mutex
+	revisions := make(map[string]int)
+
+	// checkRevisions invalidates the problems whose revision changed since the last problems.list
+	checkRevisions := func(body []byte) {
+		wrapper := wrapperProblemSlice{}
+		if json.Unmarshal(body, &wrapper) != nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, problem := range wrapper.Result {
+			problemId := strconv.Itoa(problem.Id)
+			if revision, ok := revisions[problemId]; ok && revision != problem.Revision {
+				cache.Invalidate(problemId)
+			}
+			revisions[problemId] = problem.Revision
+		}
+	}
+
+	return func(request *Request, next Handler) (response *Response, err error) {
+		if writeEndpoints[request.Method] {
+			response, err = next(request)
+			// Even a failed save may have been applied
+			cache.Invalidate(request.ProblemId)
+			cache.Invalidate("")
+			return response, err
+		}
+
+		lifetime, ok := ttl[request.Method]
+		if !ok {
+			return next(request)
+		}
+
+		key := request.Method + "?" + canonicalParameters(request.Parameters)
+		if body, ok := cache.Get(request.ProblemId, key); ok {
+			return &Response{StatusCode: http.StatusOK, Body: body}, nil
+		}
+
+		response, err = next(request)
+		if err != nil {
+			return response, err
+		}
+		if request.Method == problemsListEp {
+			checkRevisions(response.Body)
+		}
+		cache.Set(request.ProblemId, key, response.Body, lifetime)
+		return response, err
+	}
+}
+
+// lruEntry is an element of LRUCache
+type lruEntry struct {
+	problemId string
+	key       string
+	body      []byte
+	expires   time.Time
+}
+
+// LRUCache is an in-memory Cache holding a bounded number of entries.
+// When full, the least recently used entry is evicted.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]map[string]*list.Element
+}
+
+// NewLRUCache creates an LRUCache holding at most capacity entries
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]map[string]*list.Element),
+	}
+}
+
+// Get returns a copy of the body stored under key, if it is present and not expired
+func (cache *LRUCache) Get(problemId, key string) (body []byte, ok bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	element, ok := cache.entries[problemId][key]
+	if !ok {
+		return body, false
+	}
+	entry := element.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		cache.remove(element)
+		return body, false
+	}
+	cache.order.MoveToFront(element)
+	return append([]byte(nil), entry.body...), true
+}
+
+// Set stores a copy of body under key for the duration ttl
+func (cache *LRUCache) Set(problemId, key string, body []byte, ttl time.Duration) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if element, ok := cache.entries[problemId][key]; ok {
+		cache.remove(element)
+	}
+	if cache.entries[problemId] == nil {
+		cache.entries[problemId] = make(map[string]*list.Element)
+	}
+
+	entry := &lruEntry{problemId: problemId, key: key, body: append([]byte(nil), body...), expires: time.Now().Add(ttl)}
+	cache.entries[problemId][key] = cache.order.PushFront(entry)
+	for cache.order.Len() > cache.capacity {
+		cache.remove(cache.order.Back())
+	}
+}
+
+// Invalidate removes every entry of the problem
+func (cache *LRUCache) Invalidate(problemId string) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for _, element := range cache.entries[problemId] {
+		cache.order.Remove(element)
+	}
+	delete(cache.entries, problemId)
+}
+
+// remove deletes an element from the cache. The lock must be held.
+func (cache *LRUCache) remove(element *list.Element) {
+	entry := cache.order.Remove(element).(*lruEntry)
+	delete(cache.entries[entry.problemId], entry.key)
+	if len(cache.entries[entry.problemId]) == 0 {
+		delete(cache.entries, entry.problemId)
+	}
+}
@@ -0,0 +1,77 @@
+package polygon
+
+import (
+	"testing"
+	"time"
+)
+
+// caches returns the Cache implementations under test
+func caches(t *testing.T) map[string]Cache {
+	return map[string]Cache{
+		"lru":  NewLRUCache(2),
+		"disk": NewDiskCache(t.TempDir()),
+	}
+}
+
+func TestCacheGetSet(t *testing.T) {
+	for name, cache := range caches(t) {
+		if _, ok := cache.Get("1", "problem.info"); ok {
+			t.Errorf("%s: hit on an empty cache", name)
+		}
+		cache.Set("1", "problem.info", []byte("info"), time.Minute)
+		if body, ok := cache.Get("1", "problem.info"); !ok || string(body) != "info" {
+			t.Errorf("%s: Get = %q, %v, want \"info\", true", name, body, ok)
+		}
+		if _, ok := cache.Get("2", "problem.info"); ok {
+			t.Errorf("%s: entries of problem 1 served for problem 2", name)
+		}
+
+		cache.Set("1", "expired", []byte("old"), -time.Second)
+		if _, ok := cache.Get("1", "expired"); ok {
+			t.Errorf("%s: expired entry served", name)
+		}
+
+		cache.Invalidate("1")
+		if _, ok := cache.Get("1", "problem.info"); ok {
+			t.Errorf("%s: entry served after Invalidate", name)
+		}
+	}
+}
+
+func TestCacheBodiesAreNotShared(t *testing.T) {
+	for name, cache := range caches(t) {
+		body := []byte("tests")
+		cache.Set("1", "problem.tests", body, time.Minute)
+		body[0] = 'X'
+
+		got, _ := cache.Get("1", "problem.tests")
+		if string(got) != "tests" {
+			t.Errorf("%s: modifying the stored slice changed the entry to %q", name, got)
+		}
+		got[0] = 'Y'
+		if again, _ := cache.Get("1", "problem.tests"); string(again) != "tests" {
+			t.Errorf("%s: modifying a returned body changed the entry to %q", name, again)
+		}
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	cache := NewLRUCache(2)
+	cache.Set("1", "a", []byte("a"), time.Minute)
+	cache.Set("1", "b", []byte("b"), time.Minute)
+	cache.Get("1", "a")
+	cache.Set("2", "c", []byte("c"), time.Minute)
+
+	if _, ok := cache.Get("1", "b"); ok {
+		t.Error("the least recently used entry was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		problemId := "1"
+		if key == "c" {
+			problemId = "2"
+		}
+		if _, ok := cache.Get(problemId, key); !ok {
+			t.Errorf("entry %s was evicted", key)
+		}
+	}
+}
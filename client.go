@@ -0,0 +1,65 @@
+package polygon
+
+//go:generate go run ./internal/mockgen -source client.go -output polygonmock/mock.go
+
+// ProblemReader is implemented by clients able to query problems.
+type ProblemReader interface {
+	ProblemsList(parameters map[string]string) (problems []ProblemObject, err error)
+	ProblemInfo(parameters map[string]string) (problemInfo ProblemInfoObject, err error)
+	ProblemStatements(parameters map[string]string) (statementsMap map[string]StatementObject, err error)
+	ProblemStatementResources(parameters map[string]string) (files []FileObject, err error)
+	ProblemChecker(parameters map[string]string) (checkerName string, err error)
+	ProblemValidator(parameters map[string]string) (validatorName string, err error)
+	ProblemInteractor(parameters map[string]string) (interactorName string, err error)
+	ProblemFiles(parameters map[string]string) (rsa RsaObject, err error)
+	ProblemSolutions(parameters map[string]string) (solutions []SolutionObject, err error)
+	ProblemViewFile(parameters map[string]string) (fileView string, err error)
+	ProblemViewSolution(parameters map[string]string) (solutionView string, err error)
+	ProblemScript(parameters map[string]string) (scriptView string, err error)
+	ProblemTests(parameters map[string]string) (tests []TestObject, err error)
+	ProblemTestInput(parameters map[string]string) (testInputView string, err error)
+	ProblemTestAnswer(parameters map[string]string) (testAnswerView string, err error)
+	ProblemViewTestGroup(parameters map[string]string) (testGroups []TestGroupObject, err error)
+	ProblemViewTags(parameters map[string]string) (tags []string, err error)
+	ProblemViewGeneralDescription(parameters map[string]string) (description string, err error)
+	ProblemViewGeneralTutorial(parameters map[string]string) (tutorial string, err error)
+	ProblemPackages(parameters map[string]string) (packages []PackageObject, err error)
+	ProblemPackage(parameters map[string]string) (packageData []byte, err error)
+}
+
+// ProblemWriter is implemented by clients able to modify problems.
+type ProblemWriter interface {
+	ProblemUpdateInfo(parameters map[string]string) (err error)
+	ProblemSaveStatement(parameters map[string]string) (err error)
+	ProblemSaveStatementResource(parameters map[string]string) (err error)
+	ProblemSetValidator(parameters map[string]string) (err error)
+	ProblemSetChecker(parameters map[string]string) (err error)
+	ProblemSetInteractor(parameters map[string]string) (err error)
+	ProblemSaveFile(parameters map[string]string) (err error)
+	ProblemSaveSolution(parameters map[string]string) (err error)
+	ProblemEditSolutionExtraTags(parameters map[string]string) (err error)
+	ProblemSaveScript(parameters map[string]string) (err error)
+	ProblemSaveTest(parameters map[string]string) (err error)
+	ProblemSetTestGroup(parameters map[string]string) (err error)
+	ProblemEnableGroups(parameters map[string]string) (err error)
+	ProblemEnablePoints(parameters map[string]string) (err error)
+	ProblemSaveTestGroups(parameters map[string]string) (err error)
+	ProblemSaveTags(parameters map[string]string) (err error)
+	ProblemSaveGeneralDescription(parameters map[string]string) (err error)
+	ProblemSaveGeneralTutorial(parameters map[string]string) (err error)
+}
+
+// ContestReader is implemented by clients able to query contests.
+type ContestReader interface {
+	ContestProblems(parameters map[string]string) (problems []ProblemObject, err error)
+}
+
+// Client covers every API method. It is implemented by PolygonApi,
+// and by the mock in the polygonmock package for unit tests.
+type Client interface {
+	ProblemReader
+	ProblemWriter
+	ContestReader
+}
+
+var _ Client = (*PolygonApi)(nil)
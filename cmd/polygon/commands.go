@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/lint"
+)
+
+// command maps a subcommand to an API method
+//
+// group, action : the words selecting the command, e.g. "solution save"
+//
+// args          : names of the parameters filled from positional arguments, in order.
+// A leading "@" reads the argument as a file and passes its content,
+// a trailing "?" makes the argument optional.
+//
+// defaults      : parameters used when not given on the command line
+//
+// run           : calls the API, the result is printed according to the output format
+type command struct {
+	group    string
+	action   string
+	args     []string
+	defaults map[string]string
+	summary  string
+	run      func(client polygon.Client, parameters map[string]string) (interface{}, error)
+}
+
+// testsetDefault selects the default testset of polygon
+var testsetDefault = map[string]string{"testset": "tests"}
+
+// commands returns every subcommand of the tool
+func commands() []command {
+	return []command{
+		{group: "problems", action: "list", summary: "list the problems available to the user",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemsList(parameters)
+			}},
+		{group: "contest", action: "problems", args: []string{"contestId"}, summary: "list the problems of a contest",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ContestProblems(parameters)
+			}},
+
+		{group: "problem", action: "info", summary: "show the problem info",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemInfo(parameters)
+			}},
+		{group: "problem", action: "update-info", summary: "update the problem info (--timeLimit, --memoryLimit, ...)",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemUpdateInfo(parameters)
+			}},
+
+		{group: "statements", action: "list", summary: "show the statements of every language",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemStatements(parameters)
+			}},
+		{group: "statement", action: "save", summary: "update the statement of --lang (--legend, --input, ...)",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveStatement(parameters)
+			}},
+		{group: "statement-resources", action: "list", summary: "list the statement resources",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemStatementResources(parameters)
+			}},
+		{group: "statement-resource", action: "save", args: []string{"@file"}, summary: "upload a statement resource",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveStatementResource(parameters)
+			}},
+
+		{group: "checker", action: "show", summary: "show the name of the checker",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemChecker(parameters)
+			}},
+		{group: "checker", action: "set", args: []string{"checker"}, summary: "set the checker",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSetChecker(parameters)
+			}},
+		{group: "validator", action: "show", summary: "show the name of the validator",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemValidator(parameters)
+			}},
+		{group: "validator", action: "set", args: []string{"validator"}, summary: "set the validator",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSetValidator(parameters)
+			}},
+		{group: "interactor", action: "show", summary: "show the name of the interactor",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemInteractor(parameters)
+			}},
+		{group: "interactor", action: "set", args: []string{"interactor"}, summary: "set the interactor",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSetInteractor(parameters)
+			}},
+
+		{group: "files", action: "list", summary: "list the resource, source and aux files",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemFiles(parameters)
+			}},
+		{group: "file", action: "view", args: []string{"name"}, summary: "print a file of --type resource, source or aux",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemViewFile(parameters)
+			}},
+		{group: "file", action: "save", args: []string{"@file"}, summary: "upload a file of --type resource, source or aux",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveFile(parameters)
+			}},
+
+		{group: "solutions", action: "list", summary: "list the solutions",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemSolutions(parameters)
+			}},
+		{group: "solution", action: "view", args: []string{"name"}, summary: "print a solution",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemViewSolution(parameters)
+			}},
+		{group: "solution", action: "save", args: []string{"@file"}, summary: "upload a solution (--tag MA, OK, WA, ...)",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveSolution(parameters)
+			}},
+		{group: "solution", action: "extra-tags", args: []string{"name"}, summary: "add or remove (--remove) an extra tag of a solution",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemEditSolutionExtraTags(parameters)
+			}},
+
+		{group: "script", action: "view", defaults: testsetDefault, summary: "print the generation script",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemScript(parameters)
+			}},
+		{group: "script", action: "save", args: []string{"@source"}, defaults: testsetDefault, summary: "upload the generation script",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveScript(parameters)
+			}},
+
+		{group: "tests", action: "list", defaults: testsetDefault, summary: "list the tests",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemTests(parameters)
+			}},
+		{group: "test", action: "input", args: []string{"testIndex"}, defaults: testsetDefault, summary: "print the input of a test",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemTestInput(parameters)
+			}},
+		{group: "test", action: "answer", args: []string{"testIndex"}, defaults: testsetDefault, summary: "print the answer of a test",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemTestAnswer(parameters)
+			}},
+		{group: "test", action: "save", args: []string{"testIndex", "@testInput?"}, defaults: testsetDefault, summary: "add or edit a test",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveTest(parameters)
+			}},
+		{group: "tests", action: "set-group", args: []string{"testGroup"}, defaults: testsetDefault, summary: "set the group of --testIndices",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSetTestGroup(parameters)
+			}},
+
+		{group: "groups", action: "list", defaults: testsetDefault, summary: "list the test groups",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemViewTestGroup(parameters)
+			}},
+		{group: "group", action: "save", args: []string{"group"}, defaults: testsetDefault, summary: "update a test group (--pointsPolicy, ...)",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveTestGroups(parameters)
+			}},
+		{group: "groups", action: "enable", args: []string{"enable"}, defaults: testsetDefault, summary: "enable (true) or disable (false) test groups",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemEnableGroups(parameters)
+			}},
+		{group: "points", action: "enable", args: []string{"enable"}, summary: "enable (true) or disable (false) test points",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemEnablePoints(parameters)
+			}},
+
+		{group: "tags", action: "list", summary: "list the tags",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemViewTags(parameters)
+			}},
+		{group: "tags", action: "save", args: []string{"tags"}, summary: "replace the tags by a comma separated list",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveTags(parameters)
+			}},
+
+		{group: "description", action: "view", summary: "print the general description",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemViewGeneralDescription(parameters)
+			}},
+		{group: "description", action: "save", args: []string{"@description"}, summary: "upload the general description",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveGeneralDescription(parameters)
+			}},
+		{group: "tutorial", action: "view", summary: "print the general tutorial",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemViewGeneralTutorial(parameters)
+			}},
+		{group: "tutorial", action: "save", args: []string{"@tutorial"}, summary: "upload the general tutorial",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return nil, client.ProblemSaveGeneralTutorial(parameters)
+			}},
+
+		{group: "packages", action: "list", summary: "list the packages",
+			run: func(client polygon.Client, parameters map[string]string) (interface{}, error) {
+				return client.ProblemPackages(parameters)
+			}},
+		{group: "package", action: "download", args: []string{"packageId", "output?"}, summary: "download a package, to package-<id>.zip by default",
+			run: downloadPackage},
+
+		{group: "problem", action: "lint", summary: "check the problem for common mistakes (--language, --testset, --disable rule,...)",
+			run: lintProblem},
+	}
+}
+
+// lintProblem runs the lint rules which are not disabled by the comma separated disable parameter
+func lintProblem(client polygon.Client, parameters map[string]string) (interface{}, error) {
+	config := lint.Config{Testset: parameters["testset"], Language: parameters["language"], Rules: make(map[string]lint.Severity)}
+	for _, rule := range strings.Split(parameters["disable"], ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			config.Rules[rule] = lint.Off
+		}
+	}
+	return lint.Lint(context.Background(), client, config)
+}
+
+// downloadPackage saves a package to the file named by the output parameter
+func downloadPackage(client polygon.Client, parameters map[string]string) (interface{}, error) {
+	output := parameters["output"]
+	delete(parameters, "output")
+	if output == "" {
+		output = "package-" + parameters["packageId"] + ".zip"
+	}
+
+	data, err := client.ProblemPackage(parameters)
+	if err != nil {
+		return nil, err
+	}
+	return nil, ioutil.WriteFile(output, data, 0644)
+}
+
+// sameGroup compares command groups, ignoring the plural form
+func sameGroup(a, b string) bool {
+	return strings.TrimSuffix(a, "s") == strings.TrimSuffix(b, "s")
+}
+
+// findCommand returns the command selected by group and action
+func findCommand(group, action string) (command, bool) {
+	for _, cmd := range commands() {
+		if sameGroup(cmd.group, group) && cmd.action == action {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// bindArgs fills the parameters from the positional arguments of the command
+func (cmd command) bindArgs(parameters map[string]string, args []string) error {
+	for key, value := range cmd.defaults {
+		if _, ok := parameters[key]; !ok {
+			parameters[key] = value
+		}
+	}
+
+	for i, spec := range cmd.args {
+		optional := strings.HasSuffix(spec, "?")
+		name := strings.TrimSuffix(spec, "?")
+		fromFile := strings.HasPrefix(name, "@")
+		name = strings.TrimPrefix(name, "@")
+
+		if i >= len(args) {
+			if optional {
+				continue
+			}
+			if _, ok := parameters[name]; ok {
+				continue
+			}
+			return fmt.Errorf("missing argument <%s>", name)
+		}
+
+		if !fromFile {
+			parameters[name] = args[i]
+			continue
+		}
+		content, err := ioutil.ReadFile(args[i])
+		if err != nil {
+			return err
+		}
+		parameters[name] = string(content)
+		if _, ok := parameters["name"]; !ok && name == "file" {
+			parameters["name"] = filepath.Base(args[i])
+		}
+	}
+
+	if len(args) > len(cmd.args) {
+		return fmt.Errorf("unexpected argument %q", args[len(cmd.args)])
+	}
+	return nil
+}
+
+// usage describes the positional arguments of the command
+func (cmd command) usage() string {
+	words := []string{cmd.group, cmd.action}
+	for _, spec := range cmd.args {
+		name := strings.TrimPrefix(strings.TrimSuffix(spec, "?"), "@")
+		if strings.HasPrefix(spec, "@") && name != "file" {
+			name += "-file"
+		}
+		if strings.HasSuffix(spec, "?") {
+			words = append(words, "["+name+"]")
+		} else {
+			words = append(words, "<"+name+">")
+		}
+	}
+	return strings.Join(words, " ")
+}
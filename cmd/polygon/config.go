@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/variety-jones/polygon"
+)
+
+// A sample config.json
+/*
+{
+	"default": "main",
+	"profiles": {
+		"main": {
+			"apiKey": "KEY_HERE",
+			"secret": "SECRET_HERE",
+			"problemId": "PROBLEM_ID_HERE"
+		}
+	}
+}
+*/
+
+// profile holds the credentials of a polygon account
+type profile struct {
+	ApiKey    string `json:"apiKey"`
+	Secret    string `json:"secret"`
+	ProblemId string `json:"problemId"`
+}
+
+// config is the content of the configuration file
+type config struct {
+	Default  string             `json:"default"`
+	Profiles map[string]profile `json:"profiles"`
+}
+
+// defaultConfigPath returns the location of the configuration file, honouring $POLYGON_CONFIG
+func defaultConfigPath() string {
+	if path := os.Getenv("POLYGON_CONFIG"); path != "" {
+		return path
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "polygon.json"
+	}
+	return filepath.Join(dir, "polygon", "config.json")
+}
+
+// loadProfile reads the named profile from the configuration file.
+// An empty name selects $POLYGON_PROFILE, then the default profile of the file.
+//
+// $POLYGON_API_KEY and $POLYGON_SECRET override the credentials of the profile,
+// and are enough on their own when there is no configuration file.
+func loadProfile(path, name string) (selected profile, err error) {
+	if name == "" {
+		name = os.Getenv("POLYGON_PROFILE")
+	}
+
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err) && name == "":
+		err = nil
+	case err != nil:
+		return selected, err
+	default:
+		conf := config{}
+		if err = json.Unmarshal(data, &conf); err != nil {
+			return selected, fmt.Errorf("%s: %v", path, err)
+		}
+		if name == "" {
+			name = conf.Default
+		}
+		if name != "" {
+			var ok bool
+			if selected, ok = conf.Profiles[name]; !ok {
+				return selected, fmt.Errorf("%s: no profile named %q", path, name)
+			}
+		}
+	}
+
+	if apiKey := os.Getenv("POLYGON_API_KEY"); apiKey != "" {
+		selected.ApiKey = apiKey
+	}
+	if secret := os.Getenv("POLYGON_SECRET"); secret != "" {
+		selected.Secret = secret
+	}
+	if selected.ApiKey == "" || selected.Secret == "" {
+		return selected, fmt.Errorf("no credentials found, create %s or set $POLYGON_API_KEY and $POLYGON_SECRET", path)
+	}
+	return selected, err
+}
+
+// newApi creates the API object for a profile
+func newApi(selected profile, problemId string) *polygon.PolygonApi {
+	api := &polygon.PolygonApi{
+		ApiKey:    selected.ApiKey,
+		Secret:    selected.Secret,
+		ProblemId: selected.ProblemId,
+	}
+	if problemId != "" {
+		api.ProblemId = problemId
+	}
+	return api
+}
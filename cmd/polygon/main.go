@@ -0,0 +1,209 @@
+// Command polygon exposes every endpoint of the Polygon API on the command line.
+//
+// Usage:
+//
+//	polygon [--profile NAME] [--problem ID] [--output table|json|yaml] <group> <action> [--param value ...] [args]
+//
+// Every flag which is not a global flag is passed to the API as a parameter,
+// kebab-case names are converted to camelCase, e.g. --test-index 3 becomes testIndex=3.
+// Boolean parameters such as --remove or --check-existing may be given without value.
+// Run "polygon help" for the list of commands.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/lint"
+)
+
+// Exit codes, mapped from polygon.ErrorClass, except exitLint
+// which reports lint issues of the error severity
+const (
+	exitOK        = 0
+	exitFailure   = 1
+	exitUsage     = 2
+	exitAuth      = 3
+	exitNotFound  = 4
+	exitAccess    = 5
+	exitInvalid   = 6
+	exitRateLimit = 7
+	exitServer    = 8
+	exitTransport = 9
+	exitOffline   = 10
+	exitLint      = 11
+)
+
+// exitCodes maps the class of an API error to the exit code of the tool
+var exitCodes = map[string]int{
+	polygon.ErrorClassAuth:      exitAuth,
+	polygon.ErrorClassNotFound:  exitNotFound,
+	polygon.ErrorClassAccess:    exitAccess,
+	polygon.ErrorClassInvalid:   exitInvalid,
+	polygon.ErrorClassRateLimit: exitRateLimit,
+	polygon.ErrorClassServer:    exitServer,
+	polygon.ErrorClassTransport: exitTransport,
+}
+
+// options holds the global flags
+type options struct {
+	profile   string
+	problemId string
+	output    string
+	config    string
+}
+
+// camelCase converts a kebab-case flag name to the camelCase name of a parameter
+func camelCase(name string) string {
+	parts := strings.Split(name, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// booleanFlags are the API parameters which may be given without value, e.g. --remove for remove=true.
+// An explicit value may still follow, as in --remove false or --remove=false.
+var booleanFlags = map[string]bool{
+	"remove":                         true,
+	"checkExisting":                  true,
+	"noInputs":                       true,
+	"testUseInStatements":            true,
+	"verifyInputOutputForStatements": true,
+}
+
+// parseArgs splits the command line into global options, API parameters and positional arguments
+func parseArgs(args []string) (opts options, parameters map[string]string, positional []string, err error) {
+	opts = options{output: "table", config: defaultConfigPath()}
+	parameters = make(map[string]string)
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value := strings.TrimPrefix(arg, "--"), ""
+		if index := strings.Index(name, "="); index >= 0 {
+			name, value = name[:index], name[index+1:]
+		} else if booleanFlags[camelCase(name)] {
+			value = "true"
+			if i+1 < len(args) && (args[i+1] == "true" || args[i+1] == "false") {
+				i++
+				value = args[i]
+			}
+		} else {
+			if i+1 >= len(args) {
+				return opts, parameters, positional, fmt.Errorf("flag --%s needs a value", name)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch name {
+		case "profile":
+			opts.profile = value
+		case "problem":
+			opts.problemId = value
+		case "output":
+			opts.output = value
+		case "config":
+			opts.config = value
+		default:
+			parameters[camelCase(name)] = value
+		}
+	}
+	return opts, parameters, positional, err
+}
+
+// printHelp lists every command
+func printHelp(w io.Writer) {
+	fmt.Fprintln(w, "Usage: polygon [--profile NAME] [--problem ID] [--output table|json|yaml] <group> <action> [--param value ...] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+
+	all := commands()
+	sort.SliceStable(all, func(i, j int) bool {
+		return strings.TrimSuffix(all[i].group, "s") < strings.TrimSuffix(all[j].group, "s")
+	})
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, cmd := range all {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.usage(), cmd.summary)
+	}
+	tw.Flush()
+}
+
+// exitCode maps an error to the exit code of the tool
+func exitCode(err error) int {
+	if errors.Is(err, polygon.ErrOffline) {
+		return exitOffline
+	}
+	if code, ok := exitCodes[polygon.ErrorClass(err)]; ok {
+		return code
+	}
+	return exitFailure
+}
+
+// run executes the command line and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	opts, parameters, positional, err := parseArgs(args)
+	if err != nil {
+		fmt.Fprintln(stderr, "polygon:", err)
+		return exitUsage
+	}
+	if len(positional) == 0 || positional[0] == "help" {
+		printHelp(stdout)
+		return exitOK
+	}
+	if len(positional) < 2 {
+		fmt.Fprintf(stderr, "polygon: missing action for %q, run \"polygon help\"\n", positional[0])
+		return exitUsage
+	}
+
+	cmd, ok := findCommand(positional[0], positional[1])
+	if !ok {
+		fmt.Fprintf(stderr, "polygon: unknown command %q, run \"polygon help\"\n", positional[0]+" "+positional[1])
+		return exitUsage
+	}
+	if err := cmd.bindArgs(parameters, positional[2:]); err != nil {
+		fmt.Fprintf(stderr, "polygon: %v\nusage: polygon %s\n", err, cmd.usage())
+		return exitUsage
+	}
+
+	selected, err := loadProfile(opts.config, opts.profile)
+	if err != nil {
+		fmt.Fprintln(stderr, "polygon:", err)
+		return exitUsage
+	}
+
+	result, err := cmd.run(newApi(selected, opts.problemId), parameters)
+	if err != nil {
+		fmt.Fprintln(stderr, "polygon:", err)
+		return exitCode(err)
+	}
+	if err := render(stdout, result, opts.output); err != nil {
+		fmt.Fprintln(stderr, "polygon:", err)
+		return exitFailure
+	}
+	if issues, ok := result.([]lint.Issue); ok && lint.Failed(issues) {
+		return exitLint
+	}
+	return exitOK
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
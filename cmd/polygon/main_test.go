@@ -0,0 +1,35 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args       []string
+		parameters map[string]string
+		positional []string
+	}{
+		{[]string{"test", "input", "--test-index", "3"}, map[string]string{"testIndex": "3"}, []string{"test", "input"}},
+		{[]string{"solution", "extra-tags", "--remove", "sol.cpp"}, map[string]string{"remove": "true"}, []string{"solution", "extra-tags", "sol.cpp"}},
+		{[]string{"solution", "extra-tags", "sol.cpp", "--remove"}, map[string]string{"remove": "true"}, []string{"solution", "extra-tags", "sol.cpp"}},
+		{[]string{"solution", "extra-tags", "--remove", "false", "sol.cpp"}, map[string]string{"remove": "false"}, []string{"solution", "extra-tags", "sol.cpp"}},
+		{[]string{"test", "save", "--check-existing=false", "1"}, map[string]string{"checkExisting": "false"}, []string{"test", "save", "1"}},
+		{[]string{"--", "--remove"}, map[string]string{}, []string{"--remove"}},
+	}
+	for _, test := range tests {
+		_, parameters, positional, err := parseArgs(test.args)
+		if err != nil {
+			t.Errorf("parseArgs(%q): %v", test.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(parameters, test.parameters) || !reflect.DeepEqual(positional, test.positional) {
+			t.Errorf("parseArgs(%q) = %v, %q, want %v, %q", test.args, parameters, positional, test.parameters, test.positional)
+		}
+	}
+
+	if _, _, _, err := parseArgs([]string{"test", "input", "--test-index"}); err == nil {
+		t.Error("a flag without value was accepted")
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// render prints the result of a command in the given format: table, json or yaml
+func render(w io.Writer, result interface{}, format string) error {
+	if result == nil {
+		return nil
+	}
+
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(result, "", " ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case "yaml":
+		var b strings.Builder
+		writeYAML(&b, reflect.ValueOf(result), 0)
+		_, err := io.WriteString(w, b.String())
+		return err
+	case "table", "":
+		return writeTable(w, reflect.ValueOf(result))
+	}
+	return fmt.Errorf("unknown output format %q, expected table, json or yaml", format)
+}
+
+// sortedKeys returns the keys of a map value in order
+func sortedKeys(value reflect.Value) []reflect.Value {
+	keys := value.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
+
+// cell formats a value for a table cell, nested values are printed as compact JSON
+func cell(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return strings.Replace(value.String(), "\n", `\n`, -1)
+	case reflect.Struct, reflect.Slice, reflect.Map:
+		data, _ := json.Marshal(value.Interface())
+		return string(data)
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// writeTable prints strings as is, structs as key/value pairs,
+// and slices or maps of structs as one row per element
+func writeTable(w io.Writer, value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.String:
+		text := value.String()
+		if !strings.HasSuffix(text, "\n") {
+			text += "\n"
+		}
+		_, err := io.WriteString(w, text)
+		return err
+	case reflect.Struct:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for i := 0; i < value.NumField(); i++ {
+			fmt.Fprintf(tw, "%s\t%s\n", value.Type().Field(i).Name, cell(value.Field(i)))
+		}
+		return tw.Flush()
+	case reflect.Slice, reflect.Map:
+		var keys, rows []reflect.Value
+		if value.Kind() == reflect.Map {
+			keys = sortedKeys(value)
+			for _, key := range keys {
+				rows = append(rows, value.MapIndex(key))
+			}
+		} else {
+			for i := 0; i < value.Len(); i++ {
+				rows = append(rows, value.Index(i))
+			}
+		}
+
+		elem := value.Type().Elem()
+		if elem.Kind() != reflect.Struct {
+			for i, row := range rows {
+				if keys != nil {
+					fmt.Fprintf(w, "%s\t", cell(keys[i]))
+				}
+				fmt.Fprintln(w, cell(row))
+			}
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		var header []string
+		if keys != nil {
+			header = append(header, "Key")
+		}
+		for i := 0; i < elem.NumField(); i++ {
+			header = append(header, elem.Field(i).Name)
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+		for i, row := range rows {
+			var cells []string
+			if keys != nil {
+				cells = append(cells, cell(keys[i]))
+			}
+			for j := 0; j < row.NumField(); j++ {
+				cells = append(cells, cell(row.Field(j)))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		return tw.Flush()
+	}
+	_, err := fmt.Fprintln(w, cell(value))
+	return err
+}
+
+// yamlString formats a scalar string, quoting it when it could be misread
+func yamlString(text string) string {
+	if text == "" || strings.ContainsAny(text, ":#{}[],&*?|<>=!%@`'\"\n\t\\") ||
+		strings.TrimSpace(text) != text || strings.HasPrefix(text, "-") {
+		return strconv.Quote(text)
+	}
+	switch strings.ToLower(text) {
+	case "true", "false", "yes", "no", "null", "~":
+		return strconv.Quote(text)
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+// isScalar reports whether a value is written on the same line as its key
+func isScalar(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Struct:
+		return false
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	}
+	return true
+}
+
+// writeYAML writes a value as a YAML block at the given indentation
+func writeYAML(b *strings.Builder, value reflect.Value, indent int) {
+	pad := strings.Repeat("  ", indent)
+	writeEntry := func(prefix string, item reflect.Value) {
+		if isScalar(item) {
+			fmt.Fprintf(b, "%s%s %s\n", pad, prefix, yamlScalar(item))
+			return
+		}
+		fmt.Fprintf(b, "%s%s\n", pad, prefix)
+		writeYAML(b, item, indent+1)
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			writeEntry(value.Type().Field(i).Name+":", value.Field(i))
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(value) {
+			writeEntry(yamlString(fmt.Sprint(key.Interface()))+":", value.MapIndex(key))
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			writeEntry("-", value.Index(i))
+		}
+	default:
+		fmt.Fprintf(b, "%s%s\n", pad, yamlScalar(value))
+	}
+}
+
+// yamlScalar formats a scalar value
+func yamlScalar(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return yamlString(value.String())
+	case reflect.Slice:
+		return "[]"
+	case reflect.Map:
+		return "{}"
+	}
+	return fmt.Sprint(value.Interface())
+}
@@ -0,0 +1,357 @@
+// Package cms exports polygon problems to the italian task format of CMS
+// (https://cms-dev.github.io), imported with cmsImportTask:
+//
+//	task.yaml
+//	input/input0.txt, output/output0.txt, ...
+//	check/          checker sources
+//	sol/            graders and headers
+//
+// Test groups become subtasks, matched by the codenames of their tests (000, 001, ...).
+package cms
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/judge"
+)
+
+// Config holds the settings of Export
+//
+// Name    : short name of the task, defaults to the name of the directory
+//
+// Testset : polygon testset holding the tests, defaults to "tests"
+type Config struct {
+	Name    string
+	Testset string
+}
+
+// Subtask is a group of tests scored together
+//
+// Points    : points of the subtask, the sum of the points of its tests
+//
+// Codenames : codenames of the tests of the group and of the groups it depends on, transitively
+type Subtask struct {
+	Group     string
+	Points    float64
+	Codenames []string
+}
+
+// Pattern returns the regular expression matching the codenames of the subtask
+func (subtask Subtask) Pattern() string {
+	return "^(" + strings.Join(subtask.Codenames, "|") + ")$"
+}
+
+// codename returns the name of the test at a position in the task, as given by the italian format
+func codename(position int) string {
+	return fmt.Sprintf("%03d", position)
+}
+
+// scoreParameters formats subtasks as the parameters of the GroupMin and GroupSum score types
+func scoreParameters(subtasks []Subtask) string {
+	items := make([]string, len(subtasks))
+	for i, subtask := range subtasks {
+		items[i] = fmt.Sprintf("[%s, %s]", strconv.FormatFloat(subtask.Points, 'f', -1, 64), strconv.Quote(subtask.Pattern()))
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// scoreType chooses the CMS score type of the problem: GroupMin when every group is scored with
+// COMPLETE_GROUP, the tests of the groups a subtask depends on being part of it, Sum when points
+// are given for each test and they are all worth the same, and GroupSum with the subtasks of
+// PointsSubtasks otherwise. A problem without points is worth 100.
+func scoreType(tests []polygon.TestObject, groups []polygon.TestGroupObject) (name, parameters string, err error) {
+	graph, err := polygon.NewGroupGraph(groups, tests)
+	if err != nil {
+		return name, parameters, err
+	}
+	complete := 0
+	for _, group := range groups {
+		if group.PointsPolicy == polygon.PointsPolicyCompleteGroup {
+			complete++
+		}
+	}
+	if complete > 0 && complete < len(groups) {
+		return name, parameters, fmt.Errorf("cms: some groups are scored as a whole and others test by test, " +
+			"CMS needs the same policy for every group")
+	}
+
+	if complete > 0 {
+		subtasks, err := Subtasks(tests, groups)
+		if err != nil {
+			return name, parameters, err
+		}
+		return "GroupMin", scoreParameters(subtasks), nil
+	}
+
+	for _, group := range graph.Names() {
+		if len(graph.Groups[group].Dependencies) > 0 {
+			return name, parameters, fmt.Errorf("cms: group %s has dependencies, which CMS supports only for groups scored as a whole", group)
+		}
+	}
+	if len(tests) == 0 {
+		return "Sum", "0", nil
+	}
+	points, same := graph.Tests[tests[0].Index], true
+	for _, test := range tests {
+		same = same && graph.Tests[test.Index] == points
+	}
+	switch {
+	case graph.Points() == 0:
+		return "Sum", strconv.FormatFloat(100/float64(len(tests)), 'f', -1, 64), nil
+	case same:
+		return "Sum", strconv.FormatFloat(points, 'f', -1, 64), nil
+	}
+	subtasks, err := PointsSubtasks(tests)
+	if err != nil {
+		return name, parameters, err
+	}
+	return "GroupSum", scoreParameters(subtasks), nil
+}
+
+// PointsSubtasks returns a subtask per group and points value, for tests scored one by one:
+// GroupSum scores a subtask by the fraction of its tests passed, which are thus worth the same.
+// Tests without group are split by points only. Subtasks are sorted by group name, then by the points
+// of their tests. Tests are expected to be sorted by index, their position in the slice giving their codename.
+func PointsSubtasks(tests []polygon.TestObject) (subtasks []Subtask, err error) {
+	graph, err := polygon.NewGroupGraph(nil, tests)
+	if err != nil {
+		return nil, err
+	}
+	type key struct {
+		group  string
+		points float64
+	}
+	positions := make(map[key]int)
+	for position, test := range tests {
+		group, points := strings.TrimSpace(test.Groups), graph.Tests[test.Index]
+		i, ok := positions[key{group, points}]
+		if !ok {
+			i = len(subtasks)
+			positions[key{group, points}] = i
+			subtasks = append(subtasks, Subtask{Group: group})
+		}
+		subtasks[i].Points += points
+		subtasks[i].Codenames = append(subtasks[i].Codenames, codename(position))
+	}
+
+	weight := func(subtask Subtask) float64 { return subtask.Points / float64(len(subtask.Codenames)) }
+	sort.SliceStable(subtasks, func(i, j int) bool {
+		if subtasks[i].Group != subtasks[j].Group {
+			return polygon.LessGroup(subtasks[i].Group, subtasks[j].Group)
+		}
+		return weight(subtasks[i]) < weight(subtasks[j])
+	})
+	return subtasks, nil
+}
+
+// Subtasks returns a subtask per group, sorted by group name. Tests are expected to be sorted by index,
+// their position in the slice giving their codename.
+func Subtasks(tests []polygon.TestObject, groups []polygon.TestGroupObject) (subtasks []Subtask, err error) {
+	graph, err := polygon.NewGroupGraph(groups, tests)
+	if err != nil {
+		return nil, err
+	}
+	for _, issue := range graph.Issues() {
+		if issue.Kind == polygon.GroupIssueUnknownDependency {
+			return nil, fmt.Errorf("cms: %s", issue.Message)
+		}
+	}
+
+	for _, name := range graph.Names() {
+		group := graph.Groups[name]
+		if !group.Defined {
+			continue
+		}
+		included := make(map[string]bool)
+		for _, dependency := range graph.Closure(name) {
+			included[dependency] = true
+		}
+		subtask := Subtask{Group: name, Points: group.Points}
+		for position, test := range tests {
+			if included[strings.TrimSpace(test.Groups)] {
+				subtask.Codenames = append(subtask.Codenames, codename(position))
+			}
+		}
+		subtasks = append(subtasks, subtask)
+	}
+	return subtasks, nil
+}
+
+// fileName returns the name of the input or output file of a problem, "" for the standard stream
+// that polygon names stdin or stdout, as cms expects
+func fileName(name, stream string) string {
+	if name == stream {
+		return ""
+	}
+	return name
+}
+
+// taskYAML renders task.yaml
+func taskYAML(name, title string, info polygon.ProblemInfoObject, tests []polygon.TestObject, scoreType, scoreParameters string) string {
+	var public []string
+	for position, test := range tests {
+		if test.UseInStatements {
+			public = append(public, strconv.Itoa(position))
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %s\n", strconv.Quote(name))
+	fmt.Fprintf(&b, "title: %s\n", strconv.Quote(title))
+	fmt.Fprintf(&b, "time_limit: %s\n", strconv.FormatFloat(float64(info.TimeLimit)/1000, 'f', -1, 64))
+	fmt.Fprintf(&b, "memory_limit: %d\n", info.MemoryLimit)
+	fmt.Fprintf(&b, "n_input: %d\n", len(tests))
+	fmt.Fprintf(&b, "infile: %s\n", strconv.Quote(fileName(info.InputFile, "stdin")))
+	fmt.Fprintf(&b, "outfile: %s\n", strconv.Quote(fileName(info.OutputFile, "stdout")))
+	fmt.Fprintf(&b, "public_testcases: %s\n", strconv.Quote(strings.Join(public, ",")))
+	fmt.Fprintf(&b, "score_type: %s\n", scoreType)
+	fmt.Fprintf(&b, "score_type_parameters: %s\n", scoreParameters)
+	b.WriteString("token_mode: disabled\n")
+	return b.String()
+}
+
+// isGrader tells whether a resource file is compiled with the solutions, such as an IOI grader
+func isGrader(file polygon.FileObject) bool {
+	properties := file.ResourceAdvancedProperties
+	return contains(properties.Stages, "COMPILE") && contains(properties.Assets, "SOLUTION")
+}
+
+// contains tells whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFile writes a file of the task, creating its directory
+func writeFile(dir, name string, content []byte) error {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
+
+// exportFiles writes the graders to sol, and the source of a custom checker to check
+// with the header files of the problem
+func exportFiles(client polygon.ProblemReader, dir string) error {
+	files, err := client.ProblemFiles(map[string]string{})
+	if err != nil {
+		return err
+	}
+	for _, file := range files.ResourceFiles {
+		if !isGrader(file) {
+			continue
+		}
+		content, err := client.ProblemViewFile(map[string]string{"type": "resource", "name": file.Name})
+		if err != nil {
+			return err
+		}
+		if err = writeFile(dir, path.Join("sol", file.Name), []byte(content)); err != nil {
+			return err
+		}
+	}
+
+	checker, err := client.ProblemChecker(map[string]string{})
+	if err != nil || checker == "" || strings.HasPrefix(checker, "std::") {
+		return err
+	}
+	headers, err := judge.FetchHeaders(client)
+	if err != nil {
+		return err
+	}
+	for name, content := range headers {
+		if err = writeFile(dir, path.Join("check", name), []byte(content)); err != nil {
+			return err
+		}
+	}
+	content, err := client.ProblemViewFile(map[string]string{"type": "source", "name": checker})
+	if err != nil {
+		return err
+	}
+	return writeFile(dir, path.Join("check", checker), []byte(content))
+}
+
+// Export writes the problem as a CMS task in the italian format into dir.
+//
+// Test groups become subtasks: groups scored as a whole (COMPLETE_GROUP) give the GroupMin
+// score type, where each subtask also holds the tests of the groups it depends on, and
+// tests scored one by one (EACH_TEST) give the Sum score type when they are all worth the same,
+// or the GroupSum score type with a subtask per group and points value otherwise. Resource files compiled with
+// the solutions (stages COMPILE, assets SOLUTION) are IOI graders, written to sol.
+// Standard checkers are replaced by the comparison of CMS, while the source of a custom checker
+// is written to check, and must be adapted to CMS and compiled to check/checker.
+func Export(client polygon.ProblemReader, dir string, config Config) error {
+	if config.Name == "" {
+		config.Name = filepath.Base(dir)
+	}
+	if config.Testset == "" {
+		config.Testset = "tests"
+	}
+
+	info, err := client.ProblemInfo(map[string]string{})
+	if err != nil {
+		return err
+	}
+	statements, err := client.ProblemStatements(map[string]string{})
+	if err != nil {
+		return err
+	}
+	title := config.Name
+	if statement, ok := statements["english"]; ok && statement.Name != "" {
+		title = statement.Name
+	}
+
+	tests, err := client.ProblemTests(map[string]string{"testset": config.Testset, "noInputs": "true"})
+	if err != nil {
+		return err
+	}
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Index < tests[j].Index })
+	var groups []polygon.TestGroupObject
+	for _, test := range tests {
+		if test.Groups != "" {
+			if groups, err = client.ProblemViewTestGroup(map[string]string{"testset": config.Testset}); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	scoreName, scoreParameters, err := scoreType(tests, groups)
+	if err != nil {
+		return err
+	}
+
+	yaml := taskYAML(config.Name, title, info, tests, scoreName, scoreParameters)
+	if err = writeFile(dir, "task.yaml", []byte(yaml)); err != nil {
+		return err
+	}
+	for position, test := range tests {
+		parameters := map[string]string{"testset": config.Testset, "testIndex": strconv.Itoa(test.Index)}
+		input, err := client.ProblemTestInput(parameters)
+		if err != nil {
+			return err
+		}
+		answer, err := client.ProblemTestAnswer(parameters)
+		if err != nil {
+			return err
+		}
+		if err = writeFile(dir, fmt.Sprintf("input/input%d.txt", position), []byte(input)); err != nil {
+			return err
+		}
+		if err = writeFile(dir, fmt.Sprintf("output/output%d.txt", position), []byte(answer)); err != nil {
+			return err
+		}
+	}
+	return exportFiles(client, dir)
+}
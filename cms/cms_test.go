@@ -0,0 +1,137 @@
+package cms
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/variety-jones/polygon"
+)
+
+func TestSubtasks(t *testing.T) {
+	groups := []polygon.TestGroupObject{
+		{Name: "2", PointsPolicy: polygon.PointsPolicyCompleteGroup, Dependencies: "0; 1"},
+		{Name: "0", PointsPolicy: polygon.PointsPolicyCompleteGroup},
+		{Name: "1", PointsPolicy: polygon.PointsPolicyCompleteGroup, Dependencies: "0"},
+	}
+	tests := []polygon.TestObject{
+		{Index: 1, Groups: "0"},
+		{Index: 2, Groups: "1", Points: "40"},
+		{Index: 3, Groups: "2", Points: "30"},
+		{Index: 4, Groups: "2", Points: "30"},
+	}
+	subtasks, err := Subtasks(tests, groups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Subtask{
+		{Group: "0", Codenames: []string{"000"}},
+		{Group: "1", Points: 40, Codenames: []string{"000", "001"}},
+		{Group: "2", Points: 60, Codenames: []string{"000", "001", "002", "003"}},
+	}
+	if !reflect.DeepEqual(subtasks, want) {
+		t.Errorf("got %+v, want %+v", subtasks, want)
+	}
+
+	groups[0].Dependencies = "1 7"
+	if _, err = Subtasks(tests, groups); err == nil {
+		t.Error("an unknown dependency was accepted")
+	}
+}
+
+func TestScoreType(t *testing.T) {
+	eachTest := []polygon.TestGroupObject{
+		{Name: "1", PointsPolicy: polygon.PointsPolicyEachTest},
+		{Name: "2", PointsPolicy: polygon.PointsPolicyEachTest},
+	}
+	tests := []struct {
+		name       string
+		tests      []polygon.TestObject
+		groups     []polygon.TestGroupObject
+		score      string
+		parameters string
+		err        bool
+	}{
+		{
+			name:       "no points",
+			tests:      []polygon.TestObject{{Index: 1}, {Index: 2}, {Index: 3}, {Index: 4}},
+			score:      "Sum",
+			parameters: "25",
+		},
+		{
+			name:       "same points",
+			tests:      []polygon.TestObject{{Index: 1, Groups: "1", Points: "50"}, {Index: 2, Groups: "2", Points: "50"}},
+			groups:     eachTest,
+			score:      "Sum",
+			parameters: "50",
+		},
+		{
+			name: "different points",
+			tests: []polygon.TestObject{
+				{Index: 1, Groups: "1", Points: "10"},
+				{Index: 2, Groups: "1", Points: "20"},
+				{Index: 3, Groups: "2", Points: "35"},
+				{Index: 4, Groups: "2", Points: "35"},
+			},
+			groups:     eachTest,
+			score:      "GroupSum",
+			parameters: `[[10, "^(000)$"], [20, "^(001)$"], [70, "^(002|003)$"]]`,
+		},
+		{
+			name:       "complete groups",
+			tests:      []polygon.TestObject{{Index: 1, Groups: "1", Points: "30"}, {Index: 2, Groups: "2", Points: "70"}},
+			groups:     []polygon.TestGroupObject{{Name: "1", PointsPolicy: polygon.PointsPolicyCompleteGroup}, {Name: "2", PointsPolicy: polygon.PointsPolicyCompleteGroup, Dependencies: "1"}},
+			score:      "GroupMin",
+			parameters: `[[30, "^(000)$"], [70, "^(000|001)$"]]`,
+		},
+		{
+			name:   "mixed policies",
+			tests:  []polygon.TestObject{{Index: 1, Groups: "1"}},
+			groups: []polygon.TestGroupObject{eachTest[0], {Name: "2", PointsPolicy: polygon.PointsPolicyCompleteGroup}},
+			err:    true,
+		},
+		{
+			name:   "each test dependencies",
+			tests:  []polygon.TestObject{{Index: 1, Groups: "1"}},
+			groups: []polygon.TestGroupObject{{Name: "1", PointsPolicy: polygon.PointsPolicyEachTest, Dependencies: ";2"}, eachTest[1]},
+			err:    true,
+		},
+		{
+			name:  "invalid points",
+			tests: []polygon.TestObject{{Index: 1, Points: "many"}},
+			err:   true,
+		},
+	}
+	for _, test := range tests {
+		score, parameters, err := scoreType(test.tests, test.groups)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: got %s %s, want an error", test.name, score, parameters)
+			}
+			continue
+		}
+		if err != nil || score != test.score || parameters != test.parameters {
+			t.Errorf("%s: got %s %s, %v, want %s %s", test.name, score, parameters, err, test.score, test.parameters)
+		}
+	}
+}
+
+func TestTaskYAMLFiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputFile  string
+		outputFile string
+		want       string
+	}{
+		{"standard streams", "stdin", "stdout", "infile: \"\"\noutfile: \"\"\n"},
+		{"empty", "", "", "infile: \"\"\noutfile: \"\"\n"},
+		{"files", "input.txt", "output.txt", "infile: \"input.txt\"\noutfile: \"output.txt\"\n"},
+	}
+	for _, test := range tests {
+		info := polygon.ProblemInfoObject{InputFile: test.inputFile, OutputFile: test.outputFile, TimeLimit: 1000, MemoryLimit: 256}
+		yaml := taskYAML("a", "A", info, []polygon.TestObject{{Index: 1}}, "Sum", "100")
+		if !strings.Contains(yaml, test.want) {
+			t.Errorf("%s: got\n%s\nwant it to contain\n%s", test.name, yaml, test.want)
+		}
+	}
+}
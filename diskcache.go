@@ -0,0 +1,102 @@
+package polygon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// DiskCache is a Cache persisting its entries as files under a directory,
+// so that they survive restarts and can be shared between processes.
+//
+// Each problem gets its own sub-directory, each entry is stored in a file named
+// after the hash of its key, holding the expiry time on the first line followed by the body.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache creates a DiskCache storing its entries under dir
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{Dir: dir}
+}
+
+// problemDir returns the directory holding the entries of a problem
+func (cache *DiskCache) problemDir(problemId string) string {
+	if problemId == "" {
+		return filepath.Join(cache.Dir, "_")
+	}
+	return filepath.Join(cache.Dir, url.PathEscape(problemId))
+}
+
+// entryPath returns the file holding an entry
+func (cache *DiskCache) entryPath(problemId, key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(cache.problemDir(problemId), hex.EncodeToString(hash[:]))
+}
+
+// Get returns the body stored under key, if it is present and not expired
+func (cache *DiskCache) Get(problemId, key string) (body []byte, ok bool) {
+	path := cache.entryPath(problemId, key)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return body, false
+	}
+
+	index := bytes.IndexByte(data, '\n')
+	if index < 0 {
+		return body, false
+	}
+	expires, err := strconv.ParseInt(string(data[:index]), 10, 64)
+	if err != nil || time.Now().UnixNano() > expires {
+		os.Remove(path)
+		return body, false
+	}
+	return data[index+1:], true
+}
+
+// Set stores body under key for the duration ttl.
+// Errors are ignored, a failed write only results in a cache miss.
+func (cache *DiskCache) Set(problemId, key string, body []byte, ttl time.Duration) {
+	dir := cache.problemDir(problemId)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(ttl).UnixNano(), 10)
+	writeFileAtomic(cache.entryPath(problemId, key), append([]byte(expires+"\n"), body...))
+}
+
+// Invalidate removes every entry of the problem
+func (cache *DiskCache) Invalidate(problemId string) {
+	os.RemoveAll(cache.problemDir(problemId))
+}
+
+// writeFileAtomic writes data to a temporary file and renames it to path,
+// so that readers never observe a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
@@ -0,0 +1,175 @@
+// Package domjudge exports polygon problems as zip archives importable by DOMjudge.
+// The archive is a Kattis problem package (see the kattis package) with a domjudge-problem.ini.
+package domjudge
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/kattis"
+)
+
+// Config holds the settings of Export
+//
+// ShortName : short name of the problem in the contest, e.g. "A"
+//
+// Color     : color of the balloon, as an html color, optional
+//
+// Testset   : polygon testset holding the tests, defaults to "tests"
+type Config struct {
+	ShortName string
+	Color     string
+	Testset   string
+}
+
+// ErrCheckerLanguage is returned by Export for a custom checker which is not a C++ source file,
+// as DOMjudge could not build it as the special compare script
+var ErrCheckerLanguage = errors.New("domjudge: only C++ checkers can be exported")
+
+// buildScript compiles a testlib checker written in C++
+const buildScript = `#!/bin/sh
+g++ -O2 -std=c++17 -o checker %s
+`
+
+// runScript adapts a testlib checker to the interface of DOMjudge output validators:
+// "run input answer feedbackdir < output", exiting with 42 for a correct answer and 43 for a wrong one
+const runScript = `#!/bin/sh
+input="$1"
+answer="$2"
+feedback="$3"
+cat > "$feedback/output.txt"
+"$(dirname "$0")/checker" "$input" "$feedback/output.txt" "$answer" 2> "$feedback/judgemessage.txt"
+case $? in
+0) exit 42 ;;
+1|2|4|8) exit 43 ;;
+*) exit 1 ;;
+esac
+`
+
+// problemINI renders domjudge-problem.ini
+func problemINI(config Config, name string, info polygon.ProblemInfoObject) string {
+	var b strings.Builder
+	if config.ShortName != "" {
+		fmt.Fprintf(&b, "short-name = %s\n", config.ShortName)
+	}
+	fmt.Fprintf(&b, "name = %s\n", strconv.Quote(name))
+	fmt.Fprintf(&b, "timelimit = %s\n", strconv.FormatFloat(float64(info.TimeLimit)/1000, 'f', -1, 64))
+	if config.Color != "" {
+		fmt.Fprintf(&b, "color = %s\n", config.Color)
+	}
+	return b.String()
+}
+
+// addCompareScript writes the build and run scripts which turn a C++ testlib checker,
+// exported to output_validators by the kattis package, into a DOMjudge special compare script
+func addCompareScript(client polygon.ProblemReader, dir string, info polygon.ProblemInfoObject) error {
+	if info.Interactive {
+		return nil
+	}
+	checker, err := client.ProblemChecker(map[string]string{})
+	if err != nil || checker == "" || strings.HasPrefix(checker, "std::") {
+		return err
+	}
+	files, err := client.ProblemFiles(map[string]string{})
+	if err != nil {
+		return err
+	}
+	for _, file := range files.SourceFiles {
+		if file.Name != checker {
+			continue
+		}
+		if !strings.HasPrefix(file.SourceType, "cpp") {
+			return fmt.Errorf("%w: checker %s has source type %s", ErrCheckerLanguage, checker, file.SourceType)
+		}
+		validator := filepath.Join(dir, "output_validators", strings.TrimSuffix(checker, path.Ext(checker)))
+		if err = ioutil.WriteFile(filepath.Join(validator, "build"), []byte(fmt.Sprintf(buildScript, checker)), 0755); err != nil {
+			return err
+		}
+		return ioutil.WriteFile(filepath.Join(validator, "run"), []byte(runScript), 0755)
+	}
+	return fmt.Errorf("domjudge: checker %s is not among the source files", checker)
+}
+
+// writeZip archives the files of dir, keeping the executable bit of scripts
+func writeZip(w io.Writer, dir string) error {
+	archive := zip.NewWriter(w)
+	err := filepath.Walk(dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		relative, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name, header.Method = filepath.ToSlash(relative), zip.Deflate
+
+		writer, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		content, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer content.Close()
+		_, err = io.Copy(writer, content)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// Export writes the problem as a DOMjudge problem zip to w: the Kattis package of the problem,
+// with its samples, secret tests and jury submissions sorted by expected result, and a
+// domjudge-problem.ini holding the time limit. A custom C++ checker comes with build and run
+// scripts, so that DOMjudge uses it as the special compare script of the problem, other
+// custom checkers give ErrCheckerLanguage.
+//
+// The names of the solutions whose tag has no expected result in DOMjudge (RJ and TO) are returned.
+func Export(client polygon.ProblemReader, w io.Writer, config Config) (skipped []string, err error) {
+	dir, err := ioutil.TempDir("", "polygon-domjudge-")
+	if err != nil {
+		return skipped, err
+	}
+	defer os.RemoveAll(dir)
+
+	if skipped, err = kattis.Export(client, dir, kattis.Config{Testset: config.Testset}); err != nil {
+		return skipped, err
+	}
+
+	info, err := client.ProblemInfo(map[string]string{})
+	if err != nil {
+		return skipped, err
+	}
+	statements, err := client.ProblemStatements(map[string]string{})
+	if err != nil {
+		return skipped, err
+	}
+	name := config.ShortName
+	if statement, ok := statements["english"]; ok {
+		name = statement.Name
+	}
+	ini := problemINI(config, name, info)
+	if err = ioutil.WriteFile(filepath.Join(dir, "domjudge-problem.ini"), []byte(ini), 0644); err != nil {
+		return skipped, err
+	}
+	if err = addCompareScript(client, dir, info); err != nil {
+		return skipped, err
+	}
+	return skipped, writeZip(w, dir)
+}
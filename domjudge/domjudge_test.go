@@ -0,0 +1,98 @@
+package domjudge
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/polygonmock"
+)
+
+// problem returns a mock of a problem with one sample, two solutions and a custom checker of the given source type
+func problem(checkerType string) *polygonmock.Client {
+	return &polygonmock.Client{
+		ProblemInfoFunc: func(map[string]string) (polygon.ProblemInfoObject, error) {
+			return polygon.ProblemInfoObject{TimeLimit: 2500, MemoryLimit: 256}, nil
+		},
+		ProblemStatementsFunc: func(map[string]string) (map[string]polygon.StatementObject, error) {
+			return map[string]polygon.StatementObject{"english": {Name: "A + B"}}, nil
+		},
+		ProblemCheckerFunc: func(map[string]string) (string, error) { return "check.cpp", nil },
+		ProblemFilesFunc: func(map[string]string) (polygon.RsaObject, error) {
+			return polygon.RsaObject{SourceFiles: []polygon.FileObject{{Name: "check.cpp", SourceType: checkerType}}}, nil
+		},
+		ProblemViewFileFunc: func(parameters map[string]string) (string, error) { return "// " + parameters["name"], nil },
+		ProblemTestsFunc: func(map[string]string) ([]polygon.TestObject, error) {
+			return []polygon.TestObject{{Index: 1, UseInStatements: true}, {Index: 2}}, nil
+		},
+		ProblemTestInputFunc:  func(map[string]string) (string, error) { return "1 2\n", nil },
+		ProblemTestAnswerFunc: func(map[string]string) (string, error) { return "3\n", nil },
+		ProblemSolutionsFunc: func(map[string]string) ([]polygon.SolutionObject, error) {
+			return []polygon.SolutionObject{{Name: "sol.cpp", Tag: "MA"}, {Name: "maybe.cpp", Tag: "RJ"}}, nil
+		},
+		ProblemViewSolutionFunc: func(parameters map[string]string) (string, error) { return "// " + parameters["name"], nil },
+	}
+}
+
+func TestExport(t *testing.T) {
+	var archive bytes.Buffer
+	skipped, err := Export(problem("cpp.g++17"), &archive, Config{ShortName: "A", Color: "#ff0000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"maybe.cpp"}) {
+		t.Errorf("skipped %q, want maybe.cpp", skipped)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(archive.Bytes()), int64(archive.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]*zip.File)
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+	for _, name := range []string{"problem.yaml", "data/sample/01.in", "data/secret/02.ans", "submissions/accepted/sol.cpp", "output_validators/check/check.cpp"} {
+		if files[name] == nil {
+			t.Errorf("%s is missing from the archive", name)
+		}
+	}
+
+	ini, ok := files["domjudge-problem.ini"]
+	if !ok {
+		t.Fatal("domjudge-problem.ini is missing from the archive")
+	}
+	content, err := ini.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer content.Close()
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "short-name = A\nname = \"A + B\"\ntimelimit = 2.5\ncolor = #ff0000\n"; string(data) != want {
+		t.Errorf("domjudge-problem.ini is\n%s\nwant\n%s", data, want)
+	}
+
+	for _, name := range []string{"output_validators/check/build", "output_validators/check/run"} {
+		file, ok := files[name]
+		if !ok {
+			t.Errorf("%s is missing from the archive", name)
+			continue
+		}
+		if mode := file.Mode().Perm(); mode != 0755 {
+			t.Errorf("%s has mode %o, want 755", name, mode)
+		}
+	}
+}
+
+func TestExportCheckerLanguage(t *testing.T) {
+	if _, err := Export(problem("python.3"), ioutil.Discard, Config{}); !errors.Is(err, ErrCheckerLanguage) {
+		t.Errorf("got error %v, want ErrCheckerLanguage", err)
+	}
+}
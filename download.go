@@ -0,0 +1,313 @@
+package polygon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DownloadManifest is the file of the download directory recording the downloaded tests
+const DownloadManifest = ".tests.json"
+
+// manifestBatch is the number of downloaded tests after which the manifest is saved
+const manifestBatch = 32
+
+// DownloadConfig holds the settings of DownloadTests
+//
+// Testset  : testset to download, defaults to "tests"
+//
+// Workers  : number of concurrent downloads, defaults to 4
+//
+// Interval : minimum delay between the start of two requests, 0 for none
+//
+// Force    : download every test, even those which did not change since the last run
+type DownloadConfig struct {
+	Testset  string
+	Workers  int
+	Interval time.Duration
+	Force    bool
+}
+
+// DownloadResult is the outcome of the download of a test
+//
+// Skipped : the test did not change since the last download
+//
+// Err     : nil on success
+type DownloadResult struct {
+	Index   int
+	Skipped bool
+	Err     error
+}
+
+// manifestEntry records a downloaded test: the key of the test, see testKeys, and the hashes of its files
+type manifestEntry struct {
+	Key    string `json:"key"`
+	Input  string `json:"input"`
+	Answer string `json:"answer"`
+}
+
+// downloadManifest holds the entries of the downloaded tests, saved every manifestBatch tests
+type downloadManifest struct {
+	mu      sync.Mutex
+	path    string
+	pending int
+	Entries map[string]manifestEntry `json:"tests"`
+}
+
+// hashBytes returns the hex sha256 of data
+func hashBytes(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// hashFile returns the hex sha256 of a file, "" if it cannot be read
+func hashFile(path string) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return hashBytes(data)
+}
+
+// TestFileNames returns the names of the input and answer files of a test, e.g. 01.in and 01.ans
+func TestFileNames(index int) (input, answer string) {
+	name := fmt.Sprintf("%02d", index)
+	return name + ".in", name + ".ans"
+}
+
+// loadManifest reads the manifest of dir, an empty one if there is none
+func loadManifest(dir string) (*downloadManifest, error) {
+	manifest := &downloadManifest{path: filepath.Join(dir, DownloadManifest), Entries: make(map[string]manifestEntry)}
+	data, err := ioutil.ReadFile(manifest.path)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("polygon: %s: %v", manifest.path, err)
+	}
+	if manifest.Entries == nil {
+		manifest.Entries = make(map[string]manifestEntry)
+	}
+	return manifest, nil
+}
+
+// intact tells whether a test was downloaded with the same key and its files were not modified since,
+// returning the hash of its input
+func (manifest *downloadManifest) intact(dir string, index int, key string) (input string, ok bool) {
+	manifest.mu.Lock()
+	entry, ok := manifest.Entries[strconv.Itoa(index)]
+	manifest.mu.Unlock()
+	inputName, answerName := TestFileNames(index)
+	ok = ok && entry.Key == key &&
+		hashFile(filepath.Join(dir, inputName)) == entry.Input &&
+		hashFile(filepath.Join(dir, answerName)) == entry.Answer
+	return entry.Input, ok
+}
+
+// record adds a downloaded test, and saves the manifest every manifestBatch tests
+func (manifest *downloadManifest) record(index int, entry manifestEntry) error {
+	manifest.mu.Lock()
+	defer manifest.mu.Unlock()
+	manifest.Entries[strconv.Itoa(index)] = entry
+	if manifest.pending++; manifest.pending < manifestBatch {
+		return nil
+	}
+	return manifest.save()
+}
+
+// flush saves the tests recorded since the last save
+func (manifest *downloadManifest) flush() error {
+	manifest.mu.Lock()
+	defer manifest.mu.Unlock()
+	if manifest.pending == 0 {
+		return nil
+	}
+	return manifest.save()
+}
+
+// save writes the manifest atomically. The lock must be held.
+func (manifest *downloadManifest) save() error {
+	data, err := json.MarshalIndent(manifest, "", " ")
+	if err != nil {
+		return err
+	}
+	manifest.pending = 0
+	return writeFileAtomic(manifest.path, data)
+}
+
+// fileVersion identifies a revision of a file of the problem
+func fileVersion(name string, modified, length int64) string {
+	return fmt.Sprintf("%s %d %d", name, modified, length)
+}
+
+// filesVersion identifies a revision of a list of files of the problem
+func filesVersion(kind string, files []FileObject) string {
+	var b strings.Builder
+	for _, file := range files {
+		b.WriteString(kind + " " + fileVersion(file.Name, file.ModificationTimeSeconds, file.Length) + "\n")
+	}
+	return b.String()
+}
+
+// testKeys returns the key of every test, which changes with what the test may be made from:
+// the main solution and the resource files, such as the headers it includes, for every answer,
+// and the script line and every source file (generators, the validator, the checker and the
+// headers they share) for the input of a generated test. The input of manual tests is not part
+// of their key, as the tests are listed without their inputs.
+func testKeys(client ProblemReader, tests []TestObject, limiter *pacer) (keys map[int]string, err error) {
+	time.Sleep(limiter.reserve())
+	files, err := client.ProblemFiles(map[string]string{})
+	if err != nil {
+		return keys, err
+	}
+	time.Sleep(limiter.reserve())
+	solutions, err := client.ProblemSolutions(map[string]string{})
+	if err != nil {
+		return keys, err
+	}
+
+	var main string
+	for _, solution := range solutions {
+		if solution.Tag == "MA" {
+			main = fileVersion(solution.Name, solution.ModificationTimeSeconds, solution.Length)
+		}
+	}
+	answer := "main " + main + "\n" + filesVersion("resource", files.ResourceFiles) + filesVersion("aux", files.AuxFiles)
+	sources := filesVersion("source", files.SourceFiles)
+
+	keys = make(map[int]string)
+	for _, test := range tests {
+		key := answer
+		if test.Manual {
+			key += "manual"
+		} else {
+			key += "script " + test.ScriptLine + "\n" + sources
+		}
+		keys[test.Index] = hashBytes([]byte(key))
+	}
+	return keys, nil
+}
+
+// downloadTest fetches the input and answer of a test and writes them atomically. Intact generated tests
+// are skipped without any request, while the input of manual tests is fetched and compared.
+func downloadTest(client ProblemReader, dir string, test TestObject, key string, manifest *downloadManifest, limiter *pacer, config DownloadConfig) (skipped bool, err error) {
+	previous, intact := manifest.intact(dir, test.Index, key)
+	intact = intact && !config.Force
+	if intact && !test.Manual {
+		return true, nil
+	}
+
+	parameters := map[string]string{"testset": config.Testset, "testIndex": strconv.Itoa(test.Index)}
+	time.Sleep(limiter.reserve())
+	input, err := client.ProblemTestInput(parameters)
+	if err != nil {
+		return false, err
+	}
+	inputHash := hashBytes([]byte(input))
+	if intact && inputHash == previous {
+		return true, nil
+	}
+	time.Sleep(limiter.reserve())
+	answer, err := client.ProblemTestAnswer(parameters)
+	if err != nil {
+		return false, err
+	}
+
+	inputName, answerName := TestFileNames(test.Index)
+	if err = writeFileAtomic(filepath.Join(dir, inputName), []byte(input)); err != nil {
+		return false, err
+	}
+	if err = writeFileAtomic(filepath.Join(dir, answerName), []byte(answer)); err != nil {
+		return false, err
+	}
+	return false, manifest.record(test.Index, manifestEntry{Key: key, Input: inputHash, Answer: hashBytes([]byte(answer))})
+}
+
+// DownloadTests downloads the inputs and answers of the tests of a testset into dir, as the files
+// named by TestFileNames, with a pool of workers. Files are written atomically, and downloaded tests
+// are recorded in the DownloadManifest file of dir with the hashes of their files and a key made of
+// the files they are made from (see testKeys): a later run skips the generated tests whose key did
+// not change and whose files are intact, and fetches only the input of such manual tests, to compare it.
+// Files are identified by their name, modification time and length, as listed by ProblemFiles, so any
+// edit of a source or resource file downloads every generated test again. What the file lists do not
+// show, such as a change of the compilers on polygon's side, is not detected: Force downloads every
+// test again.
+//
+// The manifest is saved in batches and when the download ends. When the context is done,
+// for instance on an interrupt, the tests being downloaded are completed, the manifest is saved
+// so that the next run resumes from there, and the error of the context is returned.
+//
+// A test whose download failed has its error in its result; the returned error is also set
+// when the tests cannot be listed or the manifest cannot be read or saved.
+func DownloadTests(ctx context.Context, client ProblemReader, dir string, config DownloadConfig) (results []DownloadResult, err error) {
+	if config.Testset == "" {
+		config.Testset = "tests"
+	}
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return results, err
+	}
+	manifest, err := loadManifest(dir)
+	if err != nil {
+		return results, err
+	}
+	limiter := &pacer{interval: config.Interval}
+	time.Sleep(limiter.reserve())
+	tests, err := client.ProblemTests(map[string]string{"testset": config.Testset, "noInputs": "true"})
+	if err != nil {
+		return results, err
+	}
+	keys, err := testKeys(client, tests, limiter)
+	if err != nil {
+		return results, err
+	}
+
+	results = make([]DownloadResult, len(tests))
+	for i, test := range tests {
+		results[i].Index = test.Index
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < config.Workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i].Skipped, results[i].Err = downloadTest(client, dir, tests[i], keys[tests[i].Index], manifest, limiter, config)
+			}
+		}()
+	}
+	dispatched := 0
+dispatch:
+	for ; dispatched < len(tests) && ctx.Err() == nil; dispatched++ {
+		select {
+		case jobs <- dispatched:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := dispatched; i < len(tests); i++ {
+		results[i].Err = ctx.Err()
+	}
+	if err = manifest.flush(); err != nil {
+		return results, err
+	}
+	return results, ctx.Err()
+}
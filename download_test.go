@@ -0,0 +1,143 @@
+package polygon_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/polygonmock"
+)
+
+// downloadClient serves three tests, counting the downloads of inputs and answers
+type downloadClient struct {
+	polygonmock.Client
+	mu        sync.Mutex
+	generator int64
+	validator int64
+	header    int64
+	inputs    int
+	answers   int
+}
+
+func newDownloadClient(t *testing.T) *downloadClient {
+	client := &downloadClient{generator: 100, validator: 100, header: 100}
+	client.ProblemTestsFunc = func(parameters map[string]string) ([]polygon.TestObject, error) {
+		if parameters["noInputs"] != "true" {
+			t.Errorf("tests listed with %v, want noInputs", parameters)
+		}
+		return []polygon.TestObject{
+			{Index: 1, Manual: true},
+			{Index: 2, ScriptLine: "gen 5"},
+			{Index: 3, ScriptLine: "gen 7"},
+		}, nil
+	}
+	client.ProblemFilesFunc = func(map[string]string) (polygon.RsaObject, error) {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		return polygon.RsaObject{
+			ResourceFiles: []polygon.FileObject{{Name: "testlib.h", ModificationTimeSeconds: client.header}},
+			SourceFiles: []polygon.FileObject{
+				{Name: "gen.cpp", ModificationTimeSeconds: client.generator},
+				{Name: "val.cpp", ModificationTimeSeconds: client.validator},
+			},
+		}, nil
+	}
+	client.ProblemSolutionsFunc = func(map[string]string) ([]polygon.SolutionObject, error) {
+		return []polygon.SolutionObject{{Name: "main.cpp", Tag: "MA", ModificationTimeSeconds: 1}}, nil
+	}
+	client.ProblemTestInputFunc = func(parameters map[string]string) (string, error) {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		client.inputs++
+		return "input " + parameters["testIndex"], nil
+	}
+	client.ProblemTestAnswerFunc = func(parameters map[string]string) (string, error) {
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		client.answers++
+		return "answer " + parameters["testIndex"], nil
+	}
+	return client
+}
+
+// download runs DownloadTests, returning the number of skipped tests and of downloaded inputs and answers
+func (client *downloadClient) download(t *testing.T, dir string) (skipped, inputs, answers int) {
+	client.inputs, client.answers = 0, 0
+	results, err := polygon.DownloadTests(context.Background(), client, dir, polygon.DownloadConfig{Workers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			t.Errorf("test %d: %v", result.Index, result.Err)
+		}
+		if result.Skipped {
+			skipped++
+		}
+	}
+	return skipped, client.inputs, client.answers
+}
+
+func TestDownloadTests(t *testing.T) {
+	dir := t.TempDir()
+	client := newDownloadClient(t)
+
+	steps := []struct {
+		name                     string
+		change                   func()
+		skipped, inputs, answers int
+	}{
+		{"first download", func() {}, 0, 3, 3},
+		{"unchanged", func() {}, 3, 1, 0},
+		{"generator changed", func() { client.generator++ }, 1, 3, 2},
+		{"validator changed", func() { client.validator++ }, 1, 3, 2},
+		{"header changed", func() { client.header++ }, 0, 3, 3},
+		{"answer modified", func() { ioutil.WriteFile(filepath.Join(dir, "03.ans"), []byte("x"), 0644) }, 2, 2, 1},
+	}
+	for _, step := range steps {
+		step.change()
+		skipped, inputs, answers := client.download(t, dir)
+		if skipped != step.skipped || inputs != step.inputs || answers != step.answers {
+			t.Errorf("%s: %d skipped, %d inputs and %d answers downloaded, want %d, %d and %d",
+				step.name, skipped, inputs, answers, step.skipped, step.inputs, step.answers)
+		}
+	}
+
+	answer, err := ioutil.ReadFile(filepath.Join(dir, "03.ans"))
+	if err != nil || string(answer) != "answer 3" {
+		t.Errorf("03.ans holds %q, %v", answer, err)
+	}
+}
+
+func TestDownloadTestsInterrupted(t *testing.T) {
+	dir := t.TempDir()
+	client := newDownloadClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	answer := client.ProblemTestAnswerFunc
+	client.ProblemTestAnswerFunc = func(parameters map[string]string) (string, error) {
+		cancel()
+		return answer(parameters)
+	}
+
+	results, err := polygon.DownloadTests(ctx, client, dir, polygon.DownloadConfig{Workers: 1})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+	if results[0].Err != nil || !errors.Is(results[2].Err, context.Canceled) {
+		t.Errorf("got %v, want test 1 downloaded and test 3 canceled", results)
+	}
+	manifest, err := ioutil.ReadFile(filepath.Join(dir, polygon.DownloadManifest))
+	if err != nil || !strings.Contains(string(manifest), `"1"`) {
+		t.Errorf("manifest %s, %v, want test 1 recorded", manifest, err)
+	}
+
+	client.ProblemTestAnswerFunc = answer
+	if skipped, _, answers := client.download(t, dir); skipped == 0 || answers+skipped != 3 {
+		t.Errorf("resumed download skipped %d tests and downloaded %d answers, want the downloaded tests skipped", skipped, answers)
+	}
+}
@@ -44,3 +44,25 @@ const (
 	problemPackageEp                = "problem.package"
 	contestProblemsEp               = "contest.problems"
 )
+
+// writeEndpoints are the endpoints which modify the problem they are called on
+var writeEndpoints = map[string]bool{
+	problemUpdateInfoEp:             true,
+	problemSaveStatementEp:          true,
+	problemSaveStatementResourceEp:  true,
+	problemSetValidatorEp:           true,
+	problemSetCheckerEp:             true,
+	problemSetInteractorEp:          true,
+	problemSaveFileEp:               true,
+	problemSaveSolutionEp:           true,
+	problemEditSolutionExtraTagsEp:  true,
+	problemSaveScriptEp:             true,
+	problemSaveTestEp:               true,
+	problemSetTestGroupEp:           true,
+	problemEnableGroupsEp:           true,
+	problemEnablePointsEp:           true,
+	problemSaveTestGroupEp:          true,
+	problemSaveTagsEp:               true,
+	problemSaveGeneralDescriptionEp: true,
+	problemSaveGeneralTutorialEp:    true,
+}
@@ -0,0 +1,96 @@
+package polygon
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Classes of errors, as reported by ErrorClass
+const (
+	ErrorClassTransport = "transport"
+	ErrorClassAuth      = "auth"
+	ErrorClassNotFound  = "not_found"
+	ErrorClassAccess    = "access_denied"
+	ErrorClassInvalid   = "invalid_parameter"
+	ErrorClassRateLimit = "rate_limited"
+	ErrorClassServer    = "server"
+	ErrorClassUnknown   = "unknown"
+)
+
+// ApiError is returned when polygon replies with a FAILED status
+//
+// Method     : the API method that failed
+//
+// StatusCode : HTTP status code of the reply
+//
+// Comment    : the comment polygon attached to the failure, usually of the form "parameter: reason"
+type ApiError struct {
+	Method     string
+	StatusCode int
+	Comment    string
+}
+
+func (e *ApiError) Error() string {
+	return "the http request returned a FAILED status with comment: " + e.Comment
+}
+
+// isTransport reports whether err comes from the network rather than from polygon or the caller
+func isTransport(err error) bool {
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// ErrorClass maps an error returned by any API method to one of the ErrorClass constants.
+// It returns an empty string for a nil error. Errors which neither come from polygon nor from
+// the network, such as JSON decoding or file errors, are classed as ErrorClassUnknown.
+//
+// Polygon does not return error codes, so the class of an ApiError is guessed from its comment.
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		if isTransport(err) {
+			return ErrorClassTransport
+		}
+		return ErrorClassUnknown
+	}
+
+	comment := strings.ToLower(apiErr.Comment)
+	parameter := ""
+	if index := strings.Index(comment, ":"); index >= 0 {
+		parameter = strings.TrimSpace(comment[:index])
+	}
+
+	switch {
+	case apiErr.StatusCode == http.StatusTooManyRequests,
+		strings.Contains(comment, "too many"),
+		strings.Contains(comment, "rate limit"):
+		return ErrorClassRateLimit
+	case parameter == "apikey", parameter == "apisig", parameter == "time",
+		strings.Contains(comment, "signature"):
+		return ErrorClassAuth
+	case strings.Contains(comment, "access"),
+		strings.Contains(comment, "permission"),
+		strings.Contains(comment, "not allowed"):
+		return ErrorClassAccess
+	case strings.Contains(comment, "not found"),
+		strings.Contains(comment, "doesn't exist"),
+		strings.Contains(comment, "does not exist"),
+		strings.Contains(comment, "can't find"):
+		return ErrorClassNotFound
+	case apiErr.StatusCode >= http.StatusInternalServerError:
+		return ErrorClassServer
+	case parameter != "":
+		return ErrorClassInvalid
+	}
+	return ErrorClassUnknown
+}
@@ -0,0 +1,66 @@
+package polygon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+)
+
+func TestErrorClass(t *testing.T) {
+	var syntaxErr *json.SyntaxError
+	jsonErr := json.Unmarshal([]byte("{"), &syntaxErr)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"url error", &url.Error{Op: "Get", URL: "https://polygon.codeforces.com", Err: errors.New("connection refused")}, ErrorClassTransport},
+		{"net error", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("timeout")}, ErrorClassTransport},
+		{"truncated body", io.ErrUnexpectedEOF, ErrorClassTransport},
+		{"wrapped transport", fmt.Errorf("reading: %w", io.ErrUnexpectedEOF), ErrorClassTransport},
+		{"json error", jsonErr, ErrorClassUnknown},
+		{"file error", &os.PathError{Op: "open", Path: "package.zip", Err: os.ErrPermission}, ErrorClassUnknown},
+		{"plain error", errors.New("config: no profile"), ErrorClassUnknown},
+		{"too many requests", &ApiError{StatusCode: http.StatusTooManyRequests}, ErrorClassRateLimit},
+		{"signature", &ApiError{StatusCode: http.StatusBadRequest, Comment: "apiSig: Incorrect signature"}, ErrorClassAuth},
+		{"access", &ApiError{StatusCode: http.StatusBadRequest, Comment: "problemId: You don't have access"}, ErrorClassAccess},
+		{"not found", &ApiError{StatusCode: http.StatusBadRequest, Comment: "problemId: Problem not found"}, ErrorClassNotFound},
+		{"server", &ApiError{StatusCode: http.StatusBadGateway}, ErrorClassServer},
+		{"invalid", &ApiError{StatusCode: http.StatusBadRequest, Comment: "testIndex: must be positive"}, ErrorClassInvalid},
+		{"unknown api error", &ApiError{StatusCode: http.StatusBadRequest, Comment: "something odd"}, ErrorClassUnknown},
+	}
+	for _, test := range tests {
+		if got := ErrorClass(test.err); got != test.want {
+			t.Errorf("%s: ErrorClass(%v) = %q, want %q", test.name, test.err, got, test.want)
+		}
+	}
+}
+
+func TestRetryInterceptorSkipsDeterministicErrors(t *testing.T) {
+	calls := 0
+	retry := RetryInterceptor(3, 0, nil)
+	_, err := retry(&Request{Method: "problem.info"}, func(*Request) (*Response, error) {
+		calls++
+		return nil, errors.New("invalid character")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("got %d calls and error %v, want 1 call and an error", calls, err)
+	}
+
+	calls = 0
+	retry(&Request{Method: "problem.info"}, func(*Request) (*Response, error) {
+		calls++
+		return nil, io.ErrUnexpectedEOF
+	})
+	if calls != 3 {
+		t.Errorf("transport error: got %d calls, want 3", calls)
+	}
+}
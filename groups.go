@@ -0,0 +1,333 @@
+package polygon
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Points policies of a TestGroupObject
+const (
+	PointsPolicyEachTest      = "EACH_TEST"
+	PointsPolicyCompleteGroup = "COMPLETE_GROUP"
+)
+
+// SampleGroup is the group polygon uses for the tests shown in statements
+const SampleGroup = "0"
+
+// ErrGroupCycle is returned by GroupGraph.TopologicalOrder when dependencies form a cycle
+var ErrGroupCycle = errors.New("polygon: the dependencies of the test groups form a cycle")
+
+// Kinds of GroupIssue
+const (
+	GroupIssueCycle             = "cycle"
+	GroupIssueUnknownDependency = "unknown-dependency"
+	GroupIssueUnknownGroup      = "unknown-group"
+	GroupIssueUngrouped         = "ungrouped"
+	GroupIssueSamplePoints      = "sample-points"
+	GroupIssuePolicy            = "policy"
+)
+
+// GroupIssue is a misconfiguration of test groups, found by GroupGraph.Issues
+//
+// Kind    : one of the GroupIssue* constants
+//
+// Message : human readable explanation
+type GroupIssue struct {
+	Kind    string
+	Message string
+}
+
+// Group is a test group with its tests
+//
+// Dependencies : groups which must be passed for this group to be scored
+//
+// Tests        : indices of the tests of the group, in order
+//
+// Points       : sum of the points of the tests of the group
+//
+// Defined      : whether the group is listed by ProblemViewTestGroup, rather than only named by tests
+type Group struct {
+	Name         string
+	PointsPolicy string
+	Dependencies []string
+	Tests        []int
+	Points       float64
+	Defined      bool
+}
+
+// GroupGraph holds the test groups of a testset and their dependencies
+//
+// Groups    : groups by name
+//
+// Ungrouped : indices of the tests without group
+//
+// Tests     : points of every test by index
+type GroupGraph struct {
+	Groups    map[string]*Group
+	Ungrouped []int
+	Tests     map[int]float64
+}
+
+// ParseDependencies splits the dependencies of a TestGroupObject, separated by commas,
+// semicolons or spaces
+func ParseDependencies(dependencies string) []string {
+	return strings.FieldsFunc(dependencies, func(c rune) bool {
+		return c == ',' || c == ';' || unicode.IsSpace(c)
+	})
+}
+
+// ParsePoints reads the points of a TestObject, 0 when they are not set
+func ParsePoints(points string) (float64, error) {
+	if strings.TrimSpace(points) == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(strings.TrimSpace(points), 64)
+}
+
+// NewGroupGraph builds the graph of the groups returned by ProblemViewTestGroup,
+// with the tests returned by ProblemTests
+func NewGroupGraph(groups []TestGroupObject, tests []TestObject) (*GroupGraph, error) {
+	graph := &GroupGraph{Groups: make(map[string]*Group), Tests: make(map[int]float64)}
+	for _, object := range groups {
+		graph.Groups[object.Name] = &Group{
+			Name:         object.Name,
+			PointsPolicy: object.PointsPolicy,
+			Dependencies: ParseDependencies(object.Dependencies),
+			Defined:      true,
+		}
+	}
+	for _, test := range tests {
+		points, err := ParsePoints(test.Points)
+		if err != nil {
+			return nil, fmt.Errorf("polygon: test %d has invalid points %q", test.Index, test.Points)
+		}
+		graph.Tests[test.Index] = points
+		name := strings.TrimSpace(test.Groups)
+		if name == "" {
+			graph.Ungrouped = append(graph.Ungrouped, test.Index)
+			continue
+		}
+		group, ok := graph.Groups[name]
+		if !ok {
+			group = &Group{Name: name}
+			graph.Groups[name] = group
+		}
+		group.Tests = append(group.Tests, test.Index)
+		group.Points += points
+	}
+	return graph, nil
+}
+
+// LessGroup orders group names numerically when both are numbers, alphabetically otherwise
+func LessGroup(a, b string) bool {
+	x, errX := strconv.Atoi(a)
+	y, errY := strconv.Atoi(b)
+	if errX == nil && errY == nil {
+		return x < y
+	}
+	return a < b
+}
+
+// Names returns the names of the groups, numbers being ordered numerically
+func (graph *GroupGraph) Names() []string {
+	names := make([]string, 0, len(graph.Groups))
+	for name := range graph.Groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return LessGroup(names[i], names[j]) })
+	return names
+}
+
+// Closure returns the group and the groups it depends on, directly or not, in the order of Names.
+// Unknown dependencies are ignored.
+func (graph *GroupGraph) Closure(name string) (names []string) {
+	included := map[string]bool{name: true}
+	for queue := []string{name}; len(queue) > 0; queue = queue[1:] {
+		group, ok := graph.Groups[queue[0]]
+		if !ok {
+			continue
+		}
+		for _, dependency := range group.Dependencies {
+			if _, ok := graph.Groups[dependency]; ok && !included[dependency] {
+				included[dependency] = true
+				queue = append(queue, dependency)
+			}
+		}
+	}
+	for _, group := range graph.Names() {
+		if included[group] {
+			names = append(names, group)
+		}
+	}
+	return names
+}
+
+// walk visits the groups depth first, dependencies before dependents, calling cycle
+// with the groups of every cycle found. Unknown dependencies are ignored.
+func (graph *GroupGraph) walk(visit func(name string), cycle func(names []string)) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var stack []string
+	var dfs func(name string)
+	dfs = func(name string) {
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, dependency := range graph.Groups[name].Dependencies {
+			if _, ok := graph.Groups[dependency]; !ok {
+				continue
+			}
+			switch state[dependency] {
+			case visiting:
+				start := len(stack) - 1
+				for stack[start] != dependency {
+					start--
+				}
+				cycle(append(append([]string{}, stack[start:]...), dependency))
+			case unvisited:
+				dfs(dependency)
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		visit(name)
+	}
+	for _, name := range graph.Names() {
+		if state[name] == unvisited {
+			dfs(name)
+		}
+	}
+}
+
+// Cycles returns the dependency cycles, each one starting and ending with the same group
+func (graph *GroupGraph) Cycles() (cycles [][]string) {
+	graph.walk(func(string) {}, func(names []string) { cycles = append(cycles, names) })
+	return cycles
+}
+
+// TopologicalOrder returns the groups, each one after its dependencies, or ErrGroupCycle
+func (graph *GroupGraph) TopologicalOrder() (order []string, err error) {
+	graph.walk(func(name string) { order = append(order, name) }, func([]string) { err = ErrGroupCycle })
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Points returns the sum of the points of every test
+func (graph *GroupGraph) Points() (total float64) {
+	for _, points := range graph.Tests {
+		total += points
+	}
+	return total
+}
+
+// Issues reports dependency cycles, dependencies on unknown groups, tests in groups which are
+// not defined, tests without group when there are groups, points given to the sample group
+// and unknown points policies
+func (graph *GroupGraph) Issues() (issues []GroupIssue) {
+	report := func(kind, format string, args ...interface{}) {
+		issues = append(issues, GroupIssue{Kind: kind, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, cycle := range graph.Cycles() {
+		report(GroupIssueCycle, "dependency cycle: %s", strings.Join(cycle, " -> "))
+	}
+	for _, name := range graph.Names() {
+		group := graph.Groups[name]
+		for _, dependency := range group.Dependencies {
+			if _, ok := graph.Groups[dependency]; !ok {
+				report(GroupIssueUnknownDependency, "group %s depends on unknown group %s", name, dependency)
+			}
+		}
+		if !group.Defined {
+			report(GroupIssueUnknownGroup, "tests %s are in group %s, which is not defined", joinIndices(group.Tests), name)
+		} else if group.PointsPolicy != PointsPolicyEachTest && group.PointsPolicy != PointsPolicyCompleteGroup {
+			report(GroupIssuePolicy, "group %s has unknown points policy %q", name, group.PointsPolicy)
+		}
+		if name == SampleGroup && group.Points > 0 {
+			report(GroupIssueSamplePoints, "sample group %s is worth %s points", name, strconv.FormatFloat(group.Points, 'f', -1, 64))
+		}
+	}
+	if len(graph.Groups) > 0 && len(graph.Ungrouped) > 0 {
+		report(GroupIssueUngrouped, "tests without group: %s", joinIndices(graph.Ungrouped))
+	}
+	return issues
+}
+
+// joinIndices formats test indices as a comma separated list
+func joinIndices(indices []int) string {
+	parts := make([]string, len(indices))
+	for i, index := range indices {
+		parts[i] = strconv.Itoa(index)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Score returns the points earned by a solution passing the tests for which passed is true,
+// in total and by group. A group is scored only when its dependencies are passed completely;
+// EACH_TEST groups earn the points of their passed tests, COMPLETE_GROUP groups earn their
+// points only when every test is passed. Groups in dependency cycles earn nothing,
+// and tests without group are scored one by one.
+func (graph *GroupGraph) Score(passed func(index int) bool) (total float64, groups map[string]float64) {
+	for _, index := range graph.Ungrouped {
+		if passed(index) {
+			total += graph.Tests[index]
+		}
+	}
+
+	groups = make(map[string]float64)
+	complete := make(map[string]bool)
+	order, err := graph.TopologicalOrder()
+	if err != nil {
+		order = nil
+		cyclic := make(map[string]bool)
+		for _, cycle := range graph.Cycles() {
+			for _, name := range cycle {
+				cyclic[name] = true
+			}
+		}
+		graph.walk(func(name string) {
+			if !cyclic[name] {
+				order = append(order, name)
+			}
+		}, func([]string) {})
+	}
+
+	for _, name := range order {
+		group := graph.Groups[name]
+		scored := true
+		for _, dependency := range group.Dependencies {
+			if _, ok := graph.Groups[dependency]; ok && !complete[dependency] {
+				scored = false
+			}
+		}
+		earned, all := 0.0, true
+		for _, index := range group.Tests {
+			if passed(index) {
+				earned += graph.Tests[index]
+			} else {
+				all = false
+			}
+		}
+		complete[name] = scored && all
+		switch {
+		case !scored:
+		case group.PointsPolicy == PointsPolicyCompleteGroup:
+			if all {
+				groups[name] = group.Points
+			}
+		default:
+			groups[name] = earned
+		}
+		total += groups[name]
+	}
+	return total, groups
+}
@@ -0,0 +1,184 @@
+package polygon
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseDependencies(t *testing.T) {
+	tests := []struct {
+		dependencies string
+		want         []string
+	}{
+		{"", nil},
+		{"1", []string{"1"}},
+		{"1,2", []string{"1", "2"}},
+		{" 1 , 2 ,", []string{"1", "2"}},
+		{"1;2 3", []string{"1", "2", "3"}},
+	}
+	for _, test := range tests {
+		got := ParseDependencies(test.dependencies)
+		if len(got) != len(test.want) || len(got) > 0 && !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseDependencies(%q) = %q, want %q", test.dependencies, got, test.want)
+		}
+	}
+}
+
+func TestGroupGraphCycles(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []TestGroupObject
+		cycles [][]string
+		order  []string
+	}{
+		{
+			name:   "chain",
+			groups: []TestGroupObject{{Name: "1", Dependencies: "0"}, {Name: "2", Dependencies: "1"}, {Name: "0"}},
+			order:  []string{"0", "1", "2"},
+		},
+		{
+			name:   "numeric order",
+			groups: []TestGroupObject{{Name: "10"}, {Name: "9"}, {Name: "a"}},
+			order:  []string{"9", "10", "a"},
+		},
+		{
+			name:   "self dependency",
+			groups: []TestGroupObject{{Name: "1", Dependencies: "1"}},
+			cycles: [][]string{{"1", "1"}},
+		},
+		{
+			name:   "cycle",
+			groups: []TestGroupObject{{Name: "1", Dependencies: "3"}, {Name: "2", Dependencies: "1"}, {Name: "3", Dependencies: "2"}, {Name: "4", Dependencies: "1"}},
+			cycles: [][]string{{"1", "3", "2", "1"}},
+		},
+		{
+			name:   "unknown dependency",
+			groups: []TestGroupObject{{Name: "1", Dependencies: "7"}},
+			order:  []string{"1"},
+		},
+	}
+	for _, test := range tests {
+		graph, err := NewGroupGraph(test.groups, nil)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if cycles := graph.Cycles(); !reflect.DeepEqual(cycles, test.cycles) {
+			t.Errorf("%s: cycles %q, want %q", test.name, cycles, test.cycles)
+		}
+		order, err := graph.TopologicalOrder()
+		if test.cycles != nil {
+			if !errors.Is(err, ErrGroupCycle) {
+				t.Errorf("%s: got order %q and error %v, want ErrGroupCycle", test.name, order, err)
+			}
+		} else if err != nil || !reflect.DeepEqual(order, test.order) {
+			t.Errorf("%s: got order %q and error %v, want %q", test.name, order, err, test.order)
+		}
+	}
+}
+
+func TestGroupGraphClosure(t *testing.T) {
+	graph, err := NewGroupGraph([]TestGroupObject{
+		{Name: "0"}, {Name: "1", Dependencies: "0"}, {Name: "2", Dependencies: "1;9"}, {Name: "3", Dependencies: "0 , 3"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string][]string{
+		"0": {"0"},
+		"2": {"0", "1", "2"},
+		"3": {"0", "3"},
+		"9": nil,
+	} {
+		if got := graph.Closure(name); !reflect.DeepEqual(got, want) {
+			t.Errorf("Closure(%s) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestGroupGraphPoints(t *testing.T) {
+	groups := []TestGroupObject{
+		{Name: "0", PointsPolicy: PointsPolicyEachTest},
+		{Name: "1", PointsPolicy: PointsPolicyCompleteGroup},
+		{Name: "2", PointsPolicy: PointsPolicyEachTest, Dependencies: "1"},
+	}
+	tests := []TestObject{
+		{Index: 1, Groups: "0"},
+		{Index: 2, Groups: "1", Points: "10"},
+		{Index: 3, Groups: "1", Points: "20"},
+		{Index: 4, Groups: "2", Points: "30"},
+		{Index: 5, Groups: "2", Points: "35.5"},
+		{Index: 6, Points: "4.5"},
+	}
+	graph, err := NewGroupGraph(groups, tests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := graph.Points(); total != 100 {
+		t.Errorf("Points() = %v, want 100", total)
+	}
+	if points := graph.Groups["2"].Points; points != 65.5 {
+		t.Errorf("group 2 is worth %v, want 65.5", points)
+	}
+
+	scores := []struct {
+		name   string
+		failed []int
+		total  float64
+		groups map[string]float64
+	}{
+		{"all passed", nil, 100, map[string]float64{"0": 0, "1": 30, "2": 65.5}},
+		{"each test", []int{5, 6}, 60, map[string]float64{"0": 0, "1": 30, "2": 30}},
+		{"complete group and dependency", []int{3}, 4.5, map[string]float64{"0": 0}},
+	}
+	for _, score := range scores {
+		failed := make(map[int]bool)
+		for _, index := range score.failed {
+			failed[index] = true
+		}
+		total, earned := graph.Score(func(index int) bool { return !failed[index] })
+		if total != score.total || !reflect.DeepEqual(earned, score.groups) {
+			t.Errorf("%s: Score() = %v, %v, want %v, %v", score.name, total, earned, score.total, score.groups)
+		}
+	}
+
+	if _, err = NewGroupGraph(groups, []TestObject{{Index: 1, Points: "ten"}}); err == nil {
+		t.Error("invalid points were accepted")
+	}
+}
+
+func TestGroupGraphCycleScoresNothing(t *testing.T) {
+	graph, err := NewGroupGraph([]TestGroupObject{
+		{Name: "1", Dependencies: "2"}, {Name: "2", Dependencies: "1"}, {Name: "3"},
+	}, []TestObject{{Index: 1, Groups: "1", Points: "10"}, {Index: 2, Groups: "2", Points: "20"}, {Index: 3, Groups: "3", Points: "70"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	total, groups := graph.Score(func(int) bool { return true })
+	if total != 70 || !reflect.DeepEqual(groups, map[string]float64{"3": 70}) {
+		t.Errorf("Score() = %v, %v, want 70, map[3:70]", total, groups)
+	}
+}
+
+func TestGroupGraphIssues(t *testing.T) {
+	graph, err := NewGroupGraph([]TestGroupObject{
+		{Name: "0", PointsPolicy: PointsPolicyEachTest},
+		{Name: "1", PointsPolicy: "SOMETIMES", Dependencies: "5"},
+	}, []TestObject{
+		{Index: 1, Groups: "0", Points: "1"},
+		{Index: 2, Groups: "2"},
+		{Index: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kinds []string
+	for _, issue := range graph.Issues() {
+		kinds = append(kinds, issue.Kind)
+	}
+	want := []string{GroupIssueSamplePoints, GroupIssueUnknownDependency, GroupIssuePolicy, GroupIssueUnknownGroup, GroupIssueUngrouped}
+	if !reflect.DeepEqual(kinds, want) {
+		t.Errorf("issues %q, want %q", kinds, want)
+	}
+}
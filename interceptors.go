@@ -0,0 +1,95 @@
+package polygon
+
+import (
+	"context"
+	"time"
+)
+
+// Request describes a single call to a Polygon API method, as seen by interceptors.
+//
+// Parameters is the map passed by the caller, it must not be modified.
+// ProblemId is empty for methods that are not bound to a problem (problems.list, contest.problems).
+// Context is the context of the call (see PolygonApi.WithContext), never nil; interceptors
+// pass a derived context to the rest of the chain on a copy of the request.
+type Request struct {
+	Method     string
+	ProblemId  string
+	Parameters map[string]string
+	Context    context.Context
+}
+
+// Response holds the raw result of a call.
+//
+// A Response may be returned along with an error, for example when Polygon
+// replies with a FAILED status, so that interceptors can inspect the status code.
+type Response struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Handler performs a call and returns its raw response.
+type Handler func(request *Request) (response *Response, err error)
+
+// Interceptor wraps a call to the API. It can inspect or short-circuit the request,
+// and must call next to let the request proceed to the rest of the chain.
+//
+// Interceptors are run in the order they appear in PolygonApi.Interceptors,
+// the first one being the outermost.
+type Interceptor func(request *Request, next Handler) (response *Response, err error)
+
+// Hooks is a set of callbacks invoked around every call. Any of them may be nil.
+//
+// BeforeRequest : called before the request is sent
+//
+// AfterResponse : called after a successful response is received
+//
+// OnError       : called when the call fails, response may be nil
+type Hooks struct {
+	BeforeRequest func(request *Request)
+	AfterResponse func(request *Request, response *Response, latency time.Duration)
+	OnError       func(request *Request, response *Response, err error, latency time.Duration)
+}
+
+// HookInterceptor returns an Interceptor that invokes the given hooks around every call.
+func HookInterceptor(hooks Hooks) Interceptor {
+	return func(request *Request, next Handler) (response *Response, err error) {
+		if hooks.BeforeRequest != nil {
+			hooks.BeforeRequest(request)
+		}
+
+		start := time.Now()
+		response, err = next(request)
+		latency := time.Since(start)
+
+		if err != nil {
+			if hooks.OnError != nil {
+				hooks.OnError(request, response, err, latency)
+			}
+			return response, err
+		}
+		if hooks.AfterResponse != nil {
+			hooks.AfterResponse(request, response, latency)
+		}
+		return response, err
+	}
+}
+
+// isProblemMethod reports whether the method operates on PolygonApi.ProblemId
+func isProblemMethod(methodName string) bool {
+	return !(methodName == problemsListEp || methodName == contestProblemsEp)
+}
+
+// handler builds the interceptor chain around sendRequest, or readMirror in offline mode
+func (api *PolygonApi) handler() Handler {
+	next := Handler(api.sendRequest)
+	if api.MirrorDir != "" {
+		next = api.readMirror
+	}
+	for i := len(api.Interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := api.Interceptors[i], next
+		next = func(request *Request) (*Response, error) {
+			return interceptor(request, inner)
+		}
+	}
+	return next
+}
@@ -0,0 +1,71 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type contextKey string
+
+func TestInterceptorOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(request *Request, next Handler) (*Response, error) {
+			calls = append(calls, name+" before")
+			response, err := next(request)
+			calls = append(calls, name+" after")
+			return response, err
+		}
+	}
+	var seen *Request
+	api := &PolygonApi{ProblemId: "7", Interceptors: []Interceptor{
+		record("outer"),
+		record("inner"),
+		func(request *Request, next Handler) (*Response, error) {
+			seen = request
+			calls = append(calls, "server")
+			return &Response{StatusCode: http.StatusOK, Body: []byte(`{"status":"OK","result":{"timeLimit":1000}}`)}, nil
+		},
+	}}
+
+	ctx := context.WithValue(context.Background(), contextKey("trace"), "abc")
+	info, err := api.WithContext(ctx).ProblemInfo(map[string]string{})
+	if err != nil || info.TimeLimit != 1000 {
+		t.Fatalf("ProblemInfo = %v, %v", info, err)
+	}
+	want := []string{"outer before", "inner before", "server", "inner after", "outer after"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls %q, want %q", calls, want)
+	}
+	if seen.Method != problemInfoEp || seen.ProblemId != "7" || seen.Context.Value(contextKey("trace")) != "abc" {
+		t.Errorf("got request %+v, want problem.info of problem 7 with the context of the caller", seen)
+	}
+
+	api.ProblemsList(map[string]string{})
+	if seen.Method != problemsListEp || seen.ProblemId != "" || seen.Context == nil {
+		t.Errorf("got request %+v, want problems.list without problem and with a background context", seen)
+	}
+}
+
+func TestHookInterceptor(t *testing.T) {
+	var events []string
+	hooks := HookInterceptor(Hooks{
+		BeforeRequest: func(*Request) { events = append(events, "before") },
+		AfterResponse: func(*Request, *Response, time.Duration) { events = append(events, "after") },
+		OnError: func(_ *Request, _ *Response, err error, _ time.Duration) {
+			events = append(events, "error "+err.Error())
+		},
+	})
+	request := &Request{Method: problemInfoEp, Context: context.Background()}
+	hooks(request, func(*Request) (*Response, error) { return &Response{StatusCode: http.StatusOK}, nil })
+	hooks(request, func(*Request) (*Response, error) { return nil, errors.New("refused") })
+
+	want := []string{"before", "after", "before", "error refused"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events %q, want %q", events, want)
+	}
+}
@@ -0,0 +1,203 @@
+// Command mockgen generates the polygonmock package from the interfaces declared in client.go.
+//
+// It is run through go generate from the root of the repository:
+//
+//	go generate
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// method is an interface method to be mocked
+type method struct {
+	name       string
+	paramNames []string
+	params     []string
+	results    []string
+}
+
+// qualify prefixes the exported identifiers of a type expression with the polygon package name
+func qualify(expr ast.Expr) ast.Expr {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("polygon"), Sel: ast.NewIdent(t.Name)}
+		}
+		return t
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: t.Len, Elt: qualify(t.Elt)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(t.Key), Value: qualify(t.Value)}
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(t.X)}
+	}
+	return expr
+}
+
+// typeString renders a type expression as source code
+func typeString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, qualify(expr))
+	return buf.String()
+}
+
+// fieldTypes flattens a field list into one name and one type per parameter or result
+func fieldTypes(fset *token.FileSet, fields *ast.FieldList) (names []string, types []string) {
+	if fields == nil {
+		return names, types
+	}
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			names = append(names, fmt.Sprintf("p%d", len(names)))
+			types = append(types, typeString(fset, field.Type))
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+			types = append(types, typeString(fset, field.Type))
+		}
+	}
+	return names, types
+}
+
+// collectMethods returns the methods declared directly in the interfaces of the file
+func collectMethods(fset *token.FileSet, file *ast.File) (methods []method) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		iface, ok := node.(*ast.InterfaceType)
+		if !ok {
+			return true
+		}
+		for _, field := range iface.Methods.List {
+			funcType, ok := field.Type.(*ast.FuncType)
+			if !ok || len(field.Names) == 0 {
+				continue
+			}
+			paramNames, params := fieldTypes(fset, funcType.Params)
+			_, results := fieldTypes(fset, funcType.Results)
+			methods = append(methods, method{
+				name:       field.Names[0].Name,
+				paramNames: paramNames,
+				params:     params,
+				results:    results,
+			})
+		}
+		return false
+	})
+	return methods
+}
+
+// generate renders the mock package
+func generate(methods []method) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`// Code generated by internal/mockgen; DO NOT EDIT.
+
+// Package polygonmock provides a mock implementation of polygon.Client for unit tests.
+package polygonmock
+
+import (
+	"sync"
+
+	"github.com/variety-jones/polygon"
+)
+
+// Call records a single call made to the mock
+type Call struct {
+	Method     string
+	Parameters map[string]string
+}
+
+// Client is a mock implementation of polygon.Client.
+//
+// Each method records the call, then delegates to the field of the same name suffixed
+// with Func. When that field is nil, the method returns zero values and a nil error.
+type Client struct {
+	mu    sync.Mutex
+	calls []Call
+
+`)
+	for _, m := range methods {
+		fmt.Fprintf(&buf, "\t%sFunc func(%s) (%s)\n", m.name, strings.Join(m.params, ", "), strings.Join(m.results, ", "))
+	}
+	buf.WriteString(`}
+
+var _ polygon.Client = (*Client)(nil)
+
+// Calls returns the calls made to the mock so far, in order
+func (m *Client) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// record appends a call to the list of calls
+func (m *Client) record(method string, parameters map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Parameters: parameters})
+}
+`)
+
+	for _, m := range methods {
+		params := make([]string, len(m.params))
+		args := m.paramNames
+		for i, param := range m.params {
+			params[i] = args[i] + " " + param
+		}
+		results := make([]string, len(m.results))
+		for i, result := range m.results {
+			results[i] = fmt.Sprintf("r%d %s", i, result)
+		}
+
+		recorded := "nil"
+		for i, param := range m.params {
+			if param == "map[string]string" {
+				recorded = args[i]
+				break
+			}
+		}
+
+		fmt.Fprintf(&buf, "\n// %s calls %sFunc\n", m.name, m.name)
+		fmt.Fprintf(&buf, "func (m *Client) %s(%s) (%s) {\n", m.name, strings.Join(params, ", "), strings.Join(results, ", "))
+		fmt.Fprintf(&buf, "\tm.record(%q, %s)\n", m.name, recorded)
+		fmt.Fprintf(&buf, "\tif m.%sFunc == nil {\n\t\treturn\n\t}\n", m.name)
+		fmt.Fprintf(&buf, "\treturn m.%sFunc(%s)\n}\n", m.name, strings.Join(args, ", "))
+	}
+	return format.Source(buf.Bytes())
+}
+
+func main() {
+	source := flag.String("source", "client.go", "file declaring the interfaces to mock")
+	output := flag.String("output", "polygonmock/mock.go", "file to write the mock to")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, *source, nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	code, err := generate(collectMethods(fset, file))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(*output), 0755); err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
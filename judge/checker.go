@@ -0,0 +1,54 @@
+package judge
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+// CheckResult is the outcome of checking the output of a solution.
+// HasPoints is set when the checker awarded Points instead of a plain verdict.
+type CheckResult struct {
+	Verdict   Verdict
+	Message   string
+	Points    float64
+	HasPoints bool
+}
+
+// Checker decides whether the output of a solution on a test is correct
+type Checker interface {
+	Check(inputPath, outputPath, answerPath string) (result CheckResult, err error)
+}
+
+// TokenChecker compares the output and the answer token by token, ignoring whitespace,
+// like testlib's wcmp. It is used when no other checker is configured.
+type TokenChecker struct{}
+
+// Check compares the tokens of the output and the answer
+func (TokenChecker) Check(inputPath, outputPath, answerPath string) (result CheckResult, err error) {
+	output, err := ioutil.ReadFile(outputPath)
+	if err != nil {
+		return result, err
+	}
+	answer, err := ioutil.ReadFile(answerPath)
+	if err != nil {
+		return result, err
+	}
+
+	found, expected := strings.Fields(string(output)), strings.Fields(string(answer))
+	for i := 0; i < len(found) && i < len(expected); i++ {
+		if found[i] != expected[i] {
+			return CheckResult{
+				Verdict: WrongAnswer,
+				Message: fmt.Sprintf("token %d differs - expected: '%s', found: '%s'", i+1, expected[i], found[i]),
+			}, nil
+		}
+	}
+	if len(found) != len(expected) {
+		return CheckResult{
+			Verdict: WrongAnswer,
+			Message: fmt.Sprintf("expected %d tokens, found %d", len(expected), len(found)),
+		}, nil
+	}
+	return CheckResult{Verdict: OK, Message: fmt.Sprintf("%d tokens", len(found))}, nil
+}
@@ -0,0 +1,107 @@
+package judge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LoadInteractor compiles the interactor of the problem.
+// ErrInteractive is returned if the problem has none.
+func (workspace *Workspace) LoadInteractor() (interactor *Program, err error) {
+	name, err := workspace.client.ProblemInteractor(map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, ErrInteractive
+	}
+	return workspace.CompileSourceFile(name)
+}
+
+// Interact runs a solution against the interactor on a test. The standard output of each
+// program is piped to the standard input of the other, and the interactor is run as
+// "interactor input output", where output is then passed to the checker.
+//
+// A failed interactor gives FL, then the limits of the solution are checked, then a WA or PE
+// of the interactor, then a crash of the solution, and finally the checker decides.
+func (workspace *Workspace) Interact(program, interactor *Program, test Test) (result TestResult, err error) {
+	result.Index, result.Group = test.Index, test.Groups
+	outputDir := filepath.Join(workspace.Dir, "outputs", program.Name)
+	if err = os.MkdirAll(outputDir, 0755); err != nil {
+		return result, err
+	}
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("%02d", test.Index))
+
+	// toInteractor carries the output of the solution, toSolution the output of the interactor
+	fromSolution, toInteractor, err := os.Pipe()
+	if err != nil {
+		return result, err
+	}
+	fromInteractor, toSolution, err := os.Pipe()
+	if err != nil {
+		fromSolution.Close()
+		toInteractor.Close()
+		return result, err
+	}
+
+	limits := workspace.Limits(program.Language)
+	var stderr, interactorOutput bytes.Buffer
+	var interactorUsage Usage
+	var solutionErr, interactorErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	// Each end is closed as soon as its process exits, so that the other one reads EOF
+	go func() {
+		defer wg.Done()
+		result.Usage, solutionErr = runProcess(program.Command, outputDir, fromInteractor, toInteractor, &stderr, limits)
+		fromInteractor.Close()
+		toInteractor.Close()
+	}()
+	go func() {
+		defer wg.Done()
+		args := []string{test.InputPath, outputPath}
+		interactorUsage, interactorErr = Execute(interactor, args, outputDir, fromSolution, toSolution, &interactorOutput, toolLimits)
+		fromSolution.Close()
+		toSolution.Close()
+	}()
+	wg.Wait()
+	if solutionErr != nil {
+		return result, solutionErr
+	}
+	if interactorErr != nil {
+		return result, interactorErr
+	}
+
+	interaction, err := testlibResult(interactorUsage, interactorOutput.String())
+	if err != nil {
+		return result, err
+	}
+	if interaction.Verdict == Failed {
+		result.Verdict, result.Message = Failed, "interactor: "+interaction.Message
+		return result, nil
+	}
+	verdict, failed := usageVerdict(result.Usage, limits, stderr.String())
+	if failed && verdict != RuntimeError {
+		result.Verdict, result.Message = verdict, strings.TrimSpace(stderr.String())
+		return result, nil
+	}
+	if interaction.Verdict != OK {
+		result.Verdict, result.Message = interaction.Verdict, "interactor: "+interaction.Message
+		return result, nil
+	}
+	if failed {
+		result.Verdict, result.Message = verdict, strings.TrimSpace(stderr.String())
+		return result, nil
+	}
+
+	check, err := workspace.config.Checker.Check(test.InputPath, outputPath, test.AnswerPath)
+	if err != nil {
+		return result, err
+	}
+	result.setCheck(check)
+	return result, nil
+}
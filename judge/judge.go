@@ -0,0 +1,528 @@
+// Package judge runs the solutions of a polygon problem locally,
+// against tests downloaded through the API.
+package judge
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/variety-jones/polygon"
+)
+
+// Verdict of a solution on a test
+type Verdict string
+
+// Verdicts, named after polygon's
+const (
+	OK                  Verdict = "OK"
+	WrongAnswer         Verdict = "WA"
+	PresentationError   Verdict = "PE"
+	TimeLimitExceeded   Verdict = "TL"
+	MemoryLimitExceeded Verdict = "ML"
+	RuntimeError        Verdict = "RE"
+	CompilationError    Verdict = "CE"
+	Failed              Verdict = "FL"
+)
+
+// ErrInteractive is returned when judging an interactive problem without an interactor
+var ErrInteractive = errors.New("judge: the interactive problem has no interactor")
+
+// Config holds the settings of a local judge, all fields are optional
+//
+// Testset        : testset to run, defaults to "tests"
+//
+// Languages      : how to compile each source type, defaults to DefaultLanguages
+//
+// Checker        : checks the outputs, defaults to the checker of the problem in Run, TokenChecker otherwise
+//
+// WorkDir        : directory for tests, binaries and outputs, defaults to a temporary directory
+//
+// Solutions      : names of the solutions to run, defaults to every solution
+//
+// CompileTimeout : time allowed to each compilation, defaults to one minute
+type Config struct {
+	Testset        string
+	Languages      map[string]Language
+	Checker        Checker
+	WorkDir        string
+	Solutions      []string
+	CompileTimeout time.Duration
+}
+
+// Test is a test downloaded to the workspace
+type Test struct {
+	polygon.TestObject
+	InputPath  string
+	AnswerPath string
+}
+
+// Program is a compiled source file, ready to be run
+type Program struct {
+	Name     string
+	Command  []string
+	Language Language
+}
+
+// CompileError is returned when a source file does not compile
+type CompileError struct {
+	Name string
+	Log  string
+}
+
+func (e *CompileError) Error() string {
+	return "judge: compilation of " + e.Name + " failed:\n" + e.Log
+}
+
+// TestResult is the outcome of a solution on a test.
+// HasPoints is set when the checker awarded Points.
+type TestResult struct {
+	Index     int
+	Group     string
+	Verdict   Verdict
+	Usage     Usage
+	Message   string
+	Points    float64
+	HasPoints bool
+}
+
+// setCheck copies the outcome of the checker to the result
+func (result *TestResult) setCheck(check CheckResult) {
+	result.Verdict, result.Message = check.Verdict, check.Message
+	result.Points, result.HasPoints = check.Points, check.HasPoints
+}
+
+// SolutionReport holds the results of a solution on every test
+//
+// Compiled       : whether the solution compiled, Tests being empty otherwise
+//
+// CompilationLog : output of the compiler when the compilation failed
+type SolutionReport struct {
+	Solution       polygon.SolutionObject
+	Compiled       bool
+	CompilationLog string
+	Tests          []TestResult
+}
+
+// Verdict returns the verdict of the solution: the verdict of its first failed test,
+// CompilationError if it did not compile, OK otherwise
+func (report SolutionReport) Verdict() Verdict {
+	if !report.Compiled {
+		return CompilationError
+	}
+	for _, test := range report.Tests {
+		if test.Verdict != OK {
+			return test.Verdict
+		}
+	}
+	return OK
+}
+
+// Report holds the results of every solution
+type Report struct {
+	Testset   string
+	Info      polygon.ProblemInfoObject
+	Solutions []SolutionReport
+}
+
+// Workspace is a directory holding the tests of a problem and compiled programs
+type Workspace struct {
+	Dir     string
+	Testset string
+	Info    polygon.ProblemInfoObject
+	Tests   []Test
+
+	// Interactor is run with the solutions by Judge when set, see Interact
+	Interactor *Program
+
+	client    polygon.ProblemReader
+	config    Config
+	temporary bool
+}
+
+// NewWorkspace downloads the info and the tests of the problem into the workspace directory.
+// The workspace must be closed to remove its temporary directory.
+func NewWorkspace(client polygon.ProblemReader, config Config) (workspace *Workspace, err error) {
+	if config.Testset == "" {
+		config.Testset = "tests"
+	}
+	if config.Languages == nil {
+		config.Languages = DefaultLanguages
+	}
+	if config.Checker == nil {
+		config.Checker = TokenChecker{}
+	}
+	if config.CompileTimeout == 0 {
+		config.CompileTimeout = time.Minute
+	}
+
+	workspace = &Workspace{Dir: config.WorkDir, Testset: config.Testset, client: client, config: config}
+	if workspace.Dir == "" {
+		if workspace.Dir, err = ioutil.TempDir("", "polygon-judge-"); err != nil {
+			return nil, err
+		}
+		workspace.temporary = true
+	}
+	if err = workspace.download(); err != nil {
+		workspace.Close()
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// Close removes the workspace directory if it was created by NewWorkspace
+func (workspace *Workspace) Close() error {
+	if !workspace.temporary {
+		return nil
+	}
+	return os.RemoveAll(workspace.Dir)
+}
+
+// download fetches the problem info and the tests
+func (workspace *Workspace) download() (err error) {
+	if workspace.Info, err = workspace.client.ProblemInfo(map[string]string{}); err != nil {
+		return err
+	}
+
+	testDir := filepath.Join(workspace.Dir, "tests", workspace.Testset)
+	if err = os.MkdirAll(testDir, 0755); err != nil {
+		return err
+	}
+
+	tests, err := workspace.client.ProblemTests(map[string]string{"testset": workspace.Testset})
+	if err != nil {
+		return err
+	}
+	for _, test := range tests {
+		parameters := map[string]string{"testset": workspace.Testset, "testIndex": strconv.Itoa(test.Index)}
+		input, err := workspace.client.ProblemTestInput(parameters)
+		if err != nil {
+			return err
+		}
+		answer, err := workspace.client.ProblemTestAnswer(parameters)
+		if err != nil {
+			return err
+		}
+
+		local := Test{
+			TestObject: test,
+			InputPath:  filepath.Join(testDir, fmt.Sprintf("%02d", test.Index)),
+			AnswerPath: filepath.Join(testDir, fmt.Sprintf("%02d.a", test.Index)),
+		}
+		if err = ioutil.WriteFile(local.InputPath, []byte(input), 0644); err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(local.AnswerPath, []byte(answer), 0644); err != nil {
+			return err
+		}
+		workspace.Tests = append(workspace.Tests, local)
+	}
+	return nil
+}
+
+// Limits returns the limits of the problem, for a program of the given language
+func (workspace *Workspace) Limits(language Language) Limits {
+	return Limits{
+		Time:                time.Duration(workspace.Info.TimeLimit) * time.Millisecond,
+		Memory:              int64(workspace.Info.MemoryLimit) << 20,
+		NoAddressSpaceLimit: language.NoAddressSpaceLimit,
+	}
+}
+
+// Compile writes a source file to the workspace and compiles it according to its source type.
+// A *CompileError is returned if the compiler fails.
+func (workspace *Workspace) Compile(name, sourceType, content string) (program *Program, err error) {
+	dir := filepath.Join(workspace.Dir, "programs", name)
+	source := Source{Name: name, Type: sourceType, Content: content}
+	return Compile(dir, workspace.config.Languages, source, workspace.config.CompileTimeout)
+}
+
+// Source is a source file to compile
+//
+// Name     : file name, e.g. "gen.cpp"
+//
+// Type     : polygon source type, e.g. "cpp.g++17"
+//
+// Content  : content of the file
+//
+// Includes : other files written next to the source, such as testlib.h, by name
+type Source struct {
+	Name     string
+	Type     string
+	Content  string
+	Includes map[string]string
+}
+
+// Compile writes a source file to dir and compiles it according to its source type,
+// using the longest matching prefix of languages. A *CompileError is returned if the compiler fails.
+func Compile(dir string, languages map[string]Language, source Source, timeout time.Duration) (program *Program, err error) {
+	language, ok := lookupLanguage(languages, source.Type)
+	if !ok {
+		return nil, fmt.Errorf("judge: no language configured for source type %q of %s", source.Type, source.Name)
+	}
+
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	for name, content := range source.Includes {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+	sourcePath := filepath.Join(dir, source.Name)
+	if err = ioutil.WriteFile(sourcePath, []byte(source.Content), 0644); err != nil {
+		return nil, err
+	}
+
+	replacer := strings.NewReplacer(
+		"{source}", sourcePath,
+		"{binary}", filepath.Join(dir, "program"),
+		"{dir}", dir,
+		"{name}", strings.TrimSuffix(source.Name, filepath.Ext(source.Name)),
+	)
+	program = &Program{Name: source.Name, Command: expand(language.Run, replacer), Language: language}
+	if len(language.Compile) == 0 {
+		return program, nil
+	}
+
+	var log bytes.Buffer
+	usage, err := runProcess(expand(language.Compile, replacer), dir, nil, &log, &log, Limits{Time: timeout})
+	if err != nil {
+		return nil, err
+	}
+	if usage.ExitCode != 0 || usage.Killed {
+		return nil, &CompileError{Name: source.Name, Log: log.String()}
+	}
+	return program, nil
+}
+
+// FetchHeaders downloads the header files (.h, .hpp) among the resource files of the problem,
+// such as testlib.h, to be used as Source.Includes
+func FetchHeaders(client polygon.ProblemReader) (headers map[string]string, err error) {
+	files, err := client.ProblemFiles(map[string]string{})
+	if err != nil {
+		return headers, err
+	}
+
+	headers = make(map[string]string)
+	for _, file := range files.ResourceFiles {
+		if ext := filepath.Ext(file.Name); ext != ".h" && ext != ".hpp" {
+			continue
+		}
+		content, err := client.ProblemViewFile(map[string]string{"type": "resource", "name": file.Name})
+		if err != nil {
+			return headers, err
+		}
+		headers[file.Name] = content
+	}
+	return headers, nil
+}
+
+// Execute runs a program with additional arguments in dir, enforcing the limits.
+// An error is only returned when the program could not be started.
+func Execute(program *Program, args []string, dir string, stdin io.Reader, stdout, stderr io.Writer, limits Limits) (usage Usage, err error) {
+	command := append(append([]string{}, program.Command...), args...)
+	return runProcess(command, dir, stdin, stdout, stderr, limits)
+}
+
+// standardStream tells whether a file name of the problem info designates the standard input or output
+func standardStream(name string) bool {
+	return name == "" || name == "stdin" || name == "stdout"
+}
+
+// Judge runs a program on a test and checks its output with the configured checker.
+// The program interacts with Interactor if the workspace has one.
+//
+// When the problem reads or writes named files (ProblemInfoObject.InputFile and OutputFile),
+// the program is run in a directory of its own holding the input under its name, and the
+// output file it leaves there is checked. A missing output file is a PresentationError.
+func (workspace *Workspace) Judge(program *Program, test Test) (result TestResult, err error) {
+	if workspace.Interactor != nil {
+		return workspace.Interact(program, workspace.Interactor, test)
+	}
+
+	result.Index, result.Group = test.Index, test.Groups
+	outputDir := filepath.Join(workspace.Dir, "outputs", program.Name)
+	if err = os.MkdirAll(outputDir, 0755); err != nil {
+		return result, err
+	}
+	outputPath := filepath.Join(outputDir, fmt.Sprintf("%02d", test.Index))
+
+	runDir := outputDir
+	inputFile, outputFile := workspace.Info.InputFile, workspace.Info.OutputFile
+	if !standardStream(inputFile) || !standardStream(outputFile) {
+		runDir = outputPath + ".run"
+		if err = os.RemoveAll(runDir); err != nil {
+			return result, err
+		}
+		if err = os.MkdirAll(runDir, 0755); err != nil {
+			return result, err
+		}
+	}
+
+	var stdin io.Reader
+	if standardStream(inputFile) {
+		input, err := os.Open(test.InputPath)
+		if err != nil {
+			return result, err
+		}
+		defer input.Close()
+		stdin = input
+	} else {
+		input, err := ioutil.ReadFile(test.InputPath)
+		if err != nil {
+			return result, err
+		}
+		if err = ioutil.WriteFile(filepath.Join(runDir, inputFile), input, 0644); err != nil {
+			return result, err
+		}
+	}
+	output, err := os.Create(outputPath)
+	if err != nil {
+		return result, err
+	}
+	defer output.Close()
+	var stdout io.Writer = output
+	if !standardStream(outputFile) {
+		stdout = ioutil.Discard
+	}
+
+	limits := workspace.Limits(program.Language)
+	var stderr bytes.Buffer
+	result.Usage, err = runProcess(program.Command, runDir, stdin, stdout, &stderr, limits)
+	if err != nil {
+		return result, err
+	}
+
+	if verdict, failed := usageVerdict(result.Usage, limits, stderr.String()); failed {
+		result.Verdict, result.Message = verdict, strings.TrimSpace(stderr.String())
+		return result, nil
+	}
+
+	if !standardStream(outputFile) {
+		content, err := ioutil.ReadFile(filepath.Join(runDir, outputFile))
+		if os.IsNotExist(err) {
+			result.Verdict, result.Message = PresentationError, "output file "+outputFile+" was not created"
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+		if _, err = output.Write(content); err != nil {
+			return result, err
+		}
+	}
+
+	check, err := workspace.config.Checker.Check(test.InputPath, outputPath, test.AnswerPath)
+	if err != nil {
+		return result, err
+	}
+	result.setCheck(check)
+	return result, nil
+}
+
+// allocationFailures are printed by common runtimes when an allocation fails
+var allocationFailures = []string{"bad_alloc", "MemoryError", "out of memory", "Cannot allocate memory"}
+
+// usageVerdict returns the verdict of a run exceeding its limits or crashing, if any.
+// A crash caused by a failed allocation under the address space rlimit is reported as MemoryLimitExceeded.
+func usageVerdict(usage Usage, limits Limits, stderr string) (verdict Verdict, failed bool) {
+	switch {
+	case usage.Killed || (limits.Time > 0 && usage.Time > limits.Time):
+		return TimeLimitExceeded, true
+	case limits.Memory > 0 && usage.Memory > limits.Memory:
+		return MemoryLimitExceeded, true
+	case usage.ExitCode != 0:
+		if limits.Memory > 0 && !limits.NoAddressSpaceLimit {
+			for _, failure := range allocationFailures {
+				if strings.Contains(stderr, failure) {
+					return MemoryLimitExceeded, true
+				}
+			}
+		}
+		return RuntimeError, true
+	}
+	return OK, false
+}
+
+// judgeSolution compiles a solution and runs it on every test
+func (workspace *Workspace) judgeSolution(solution polygon.SolutionObject) (report SolutionReport, err error) {
+	report.Solution = solution
+	source, err := workspace.client.ProblemViewSolution(map[string]string{"name": solution.Name})
+	if err != nil {
+		return report, err
+	}
+
+	program, err := workspace.Compile(solution.Name, solution.SourceType, source)
+	var compileErr *CompileError
+	if errors.As(err, &compileErr) {
+		report.CompilationLog = compileErr.Log
+		return report, nil
+	}
+	if err != nil {
+		return report, err
+	}
+
+	report.Compiled = true
+	report.Tests = []TestResult{}
+	for _, test := range workspace.Tests {
+		result, err := workspace.Judge(program, test)
+		if err != nil {
+			return report, err
+		}
+		report.Tests = append(report.Tests, result)
+	}
+	return report, nil
+}
+
+// Run judges the solutions of the problem on every test of the testset.
+// Solutions are compiled according to their SourceType, and run sequentially
+// within the time and memory limits of the problem. The checker of the problem is used
+// unless config.Checker is set, and interactive problems are run with their interactor.
+func Run(client polygon.ProblemReader, config Config) (report Report, err error) {
+	workspace, err := NewWorkspace(client, config)
+	if err != nil {
+		return report, err
+	}
+	defer workspace.Close()
+
+	report.Testset, report.Info = workspace.Testset, workspace.Info
+	if config.Checker == nil {
+		if workspace.config.Checker, err = workspace.LoadChecker(); err != nil {
+			return report, err
+		}
+	}
+	if workspace.Info.Interactive {
+		if workspace.Interactor, err = workspace.LoadInteractor(); err != nil {
+			return report, err
+		}
+	}
+
+	solutions, err := client.ProblemSolutions(map[string]string{})
+	if err != nil {
+		return report, err
+	}
+	selected := make(map[string]bool)
+	for _, name := range config.Solutions {
+		selected[name] = true
+	}
+
+	for _, solution := range solutions {
+		if len(selected) > 0 && !selected[solution.Name] {
+			continue
+		}
+		solutionReport, err := workspace.judgeSolution(solution)
+		if err != nil {
+			return report, err
+		}
+		report.Solutions = append(report.Solutions, solutionReport)
+	}
+	return report, nil
+}
@@ -0,0 +1,104 @@
+package judge
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/variety-jones/polygon"
+)
+
+func TestUsageVerdict(t *testing.T) {
+	limits := Limits{Time: time.Second, Memory: 64 << 20}
+	tests := []struct {
+		name    string
+		usage   Usage
+		limits  Limits
+		stderr  string
+		verdict Verdict
+		failed  bool
+	}{
+		{"ok", Usage{Time: time.Millisecond, Memory: 1 << 20}, limits, "", OK, false},
+		{"killed", Usage{Killed: true}, limits, "", TimeLimitExceeded, true},
+		{"slow", Usage{Time: 2 * time.Second}, limits, "", TimeLimitExceeded, true},
+		{"no time limit", Usage{Time: time.Hour}, Limits{}, "", OK, false},
+		{"memory", Usage{Memory: 65 << 20}, limits, "", MemoryLimitExceeded, true},
+		{"crash", Usage{ExitCode: 1}, limits, "segmentation fault", RuntimeError, true},
+		{"failed allocation", Usage{ExitCode: 134}, limits, "std::bad_alloc", MemoryLimitExceeded, true},
+		{"failed allocation without rlimit", Usage{ExitCode: 1}, Limits{Memory: 64 << 20, NoAddressSpaceLimit: true}, "MemoryError", RuntimeError, true},
+	}
+	for _, test := range tests {
+		verdict, failed := usageVerdict(test.usage, test.limits, test.stderr)
+		if verdict != test.verdict || failed != test.failed {
+			t.Errorf("%s: usageVerdict() = %s, %v, want %s, %v", test.name, verdict, failed, test.verdict, test.failed)
+		}
+	}
+}
+
+// shell compiles by checking the syntax of the script and runs it with sh
+var shell = map[string]Language{
+	"sh": {Compile: []string{"sh", "-n", "{source}"}, Run: []string{"sh", "{source}"}},
+}
+
+func TestJudge(t *testing.T) {
+	const sum = "read a b\necho $((a + b))\n"
+	tests := []struct {
+		name       string
+		inputFile  string
+		outputFile string
+		source     string
+		verdict    Verdict
+	}{
+		{"standard streams", "stdin", "stdout", sum, OK},
+		{"empty file names", "", "", sum, OK},
+		{"wrong answer", "", "", "echo 4\n", WrongAnswer},
+		{"runtime error", "", "", "exit 3\n", RuntimeError},
+		{"files", "input.txt", "output.txt", "read a b < input.txt\necho $((a + b)) > output.txt\n", OK},
+		{"input file only", "input.txt", "stdout", "read a b < input.txt\necho $((a + b))\n", OK},
+		{"missing output file", "input.txt", "output.txt", "read a b < input.txt\necho $((a + b))\n", PresentationError},
+	}
+	for _, test := range tests {
+		dir := t.TempDir()
+		workspace := &Workspace{
+			Dir:    dir,
+			Info:   polygon.ProblemInfoObject{InputFile: test.inputFile, OutputFile: test.outputFile, TimeLimit: 5000, MemoryLimit: 256},
+			config: Config{Languages: shell, Checker: TokenChecker{}, CompileTimeout: time.Minute},
+		}
+		input := Test{InputPath: filepath.Join(dir, "01"), AnswerPath: filepath.Join(dir, "01.a")}
+		input.Index = 1
+		if err := ioutil.WriteFile(input.InputPath, []byte("1 2\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(input.AnswerPath, []byte("3\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		program, err := workspace.Compile("sol.sh", "sh", test.source)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		result, err := workspace.Judge(program, input)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if result.Index != 1 || result.Verdict != test.verdict {
+			t.Errorf("%s: got test %d with %s (%s), want test 1 with %s", test.name, result.Index, result.Verdict, result.Message, test.verdict)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	_, err := Compile(t.TempDir(), shell, Source{Name: "bad.sh", Type: "sh", Content: "if\n"}, time.Minute)
+	var compileErr *CompileError
+	if !errors.As(err, &compileErr) || compileErr.Name != "bad.sh" || compileErr.Log == "" {
+		t.Errorf("got error %v, want a compilation error with a log", err)
+	}
+
+	if _, err = Compile(t.TempDir(), shell, Source{Name: "a.py", Type: "python.3"}, time.Minute); err == nil {
+		t.Error("compiled a source type without language")
+	}
+}
@@ -0,0 +1,87 @@
+package judge
+
+import (
+	"strings"
+)
+
+// Language describes how to compile and run sources of a polygon source type.
+//
+// Compile and Run are command templates, where the following placeholders are replaced:
+//
+// {source} : path of the source file
+//
+// {binary} : path of the compiled binary
+//
+// {dir}    : directory holding the source and the binary
+//
+// {name}   : name of the source file without its extension (e.g. the Java class name)
+//
+// Compile may be empty for interpreted languages.
+// NoAddressSpaceLimit disables the address space rlimit for runtimes reserving a lot of
+// virtual memory (such as the JVM), the memory limit is then only checked after the run.
+type Language struct {
+	Compile             []string
+	Run                 []string
+	NoAddressSpaceLimit bool
+}
+
+// DefaultLanguages maps prefixes of polygon source types (e.g. "cpp.g++17") to languages.
+// The longest matching prefix is used.
+var DefaultLanguages = map[string]Language{
+	"cpp": {
+		Compile: []string{"g++", "-O2", "-std=c++17", "-o", "{binary}", "{source}"},
+		Run:     []string{"{binary}"},
+	},
+	"c.": {
+		Compile: []string{"gcc", "-O2", "-std=c11", "-o", "{binary}", "{source}", "-lm"},
+		Run:     []string{"{binary}"},
+	},
+	"pascal": {
+		Compile: []string{"fpc", "-O2", "-o{binary}", "{source}"},
+		Run:     []string{"{binary}"},
+	},
+	"java": {
+		Compile:             []string{"javac", "-d", "{dir}", "{source}"},
+		Run:                 []string{"java", "-Xss64m", "-cp", "{dir}", "{name}"},
+		NoAddressSpaceLimit: true,
+	},
+	"kotlin": {
+		Compile:             []string{"kotlinc", "{source}", "-include-runtime", "-d", "{binary}.jar"},
+		Run:                 []string{"java", "-jar", "{binary}.jar"},
+		NoAddressSpaceLimit: true,
+	},
+	"python.2": {
+		Run: []string{"python2", "{source}"},
+	},
+	"python.3": {
+		Run: []string{"python3", "{source}"},
+	},
+	"python.pypy2": {
+		Run:                 []string{"pypy", "{source}"},
+		NoAddressSpaceLimit: true,
+	},
+	"python.pypy3": {
+		Run:                 []string{"pypy3", "{source}"},
+		NoAddressSpaceLimit: true,
+	},
+}
+
+// lookupLanguage returns the language of the longest prefix of sourceType found in languages
+func lookupLanguage(languages map[string]Language, sourceType string) (language Language, ok bool) {
+	best := -1
+	for prefix, candidate := range languages {
+		if strings.HasPrefix(sourceType, prefix) && len(prefix) > best {
+			language, best = candidate, len(prefix)
+		}
+	}
+	return language, best >= 0
+}
+
+// expand replaces the placeholders of a command template
+func expand(template []string, replacer *strings.Replacer) []string {
+	command := make([]string, len(template))
+	for i, word := range template {
+		command[i] = replacer.Replace(word)
+	}
+	return command
+}
@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd)
+
+package judge
+
+import (
+	"os"
+)
+
+// withLimits returns the command unchanged, rlimits are not available on this platform.
+// Only the wall clock limit is enforced, and the CPU time limit is checked after the run.
+func withLimits(command []string, limits Limits) []string {
+	return command
+}
+
+// peakMemory is not reported on this platform
+func peakMemory(state *os.ProcessState) int64 {
+	return 0
+}
@@ -0,0 +1,48 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+
+package judge
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// withLimits wraps command in a shell setting the rlimits of the process before exec
+func withLimits(command []string, limits Limits) []string {
+	var script []string
+	if limits.Time > 0 {
+		seconds := int64(math.Ceil(limits.Time.Seconds())) + 1
+		script = append(script, fmt.Sprintf("ulimit -t %d", seconds))
+	}
+	if limits.Memory > 0 {
+		// Like polygon, let the stack use the whole memory
+		script = append(script, fmt.Sprintf("ulimit -s %d 2>/dev/null", limits.Memory/1024))
+		if !limits.NoAddressSpaceLimit {
+			// The address space is larger than the resident memory, the limit itself is checked after the run
+			script = append(script, fmt.Sprintf("ulimit -v %d", 2*limits.Memory/1024))
+		}
+	}
+	if len(script) == 0 {
+		return command
+	}
+
+	script = append(script, `exec "$@"`)
+	return append([]string{"/bin/sh", "-c", strings.Join(script, "; "), "sh"}, command...)
+}
+
+// peakMemory returns the peak resident memory of a finished process in bytes
+func peakMemory(state *os.ProcessState) int64 {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	// Linux and the BSDs report kilobytes, macOS reports bytes
+	if runtime.GOOS == "darwin" {
+		return int64(rusage.Maxrss)
+	}
+	return int64(rusage.Maxrss) * 1024
+}
@@ -0,0 +1,85 @@
+package judge
+
+import (
+	"io"
+	"os/exec"
+	"sync/atomic"
+	"time"
+)
+
+// Limits bounds the resources of a run, zero values mean no limit.
+//
+// Time   : CPU time limit
+//
+// Memory : memory limit in bytes
+//
+// NoAddressSpaceLimit : do not enforce the memory limit with an rlimit (see Language)
+type Limits struct {
+	Time                time.Duration
+	Memory              int64
+	NoAddressSpaceLimit bool
+}
+
+// Usage describes the resources used by a finished run
+//
+// Time     : CPU time (user and system)
+//
+// WallTime : elapsed real time
+//
+// Memory   : peak resident memory in bytes, 0 if the platform does not report it
+//
+// ExitCode : exit code of the process, -1 if it was killed by a signal
+//
+// Killed   : the process was killed for exceeding the wall clock limit
+type Usage struct {
+	Time     time.Duration
+	WallTime time.Duration
+	Memory   int64
+	ExitCode int
+	Killed   bool
+}
+
+// wallClockLimit returns how long a process may run before being killed,
+// long enough not to cut a process that is only waiting on I/O
+func wallClockLimit(limits Limits) time.Duration {
+	if limits.Time <= 0 {
+		return 0
+	}
+	return 3*limits.Time + time.Second
+}
+
+// runProcess runs command in dir with the given standard streams, enforcing the limits.
+// An error is only returned when the process could not be started.
+func runProcess(command []string, dir string, stdin io.Reader, stdout, stderr io.Writer, limits Limits) (usage Usage, err error) {
+	command = withLimits(command, limits)
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Dir = dir
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	start := time.Now()
+	if err = cmd.Start(); err != nil {
+		return usage, err
+	}
+
+	var killed int32
+	if limit := wallClockLimit(limits); limit > 0 {
+		timer := time.AfterFunc(limit, func() {
+			atomic.StoreInt32(&killed, 1)
+			cmd.Process.Kill()
+		})
+		defer timer.Stop()
+	}
+
+	// The exit status is read from the process state below
+	cmd.Wait()
+	usage.WallTime = time.Since(start)
+	usage.Killed = atomic.LoadInt32(&killed) == 1
+
+	state := cmd.ProcessState
+	usage.Time = state.UserTime() + state.SystemTime()
+	usage.Memory = peakMemory(state)
+	usage.ExitCode = state.ExitCode()
+	return usage, nil
+}
@@ -0,0 +1,230 @@
+package judge
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// ExtraTag is a tag of a solution restricted to a testset or a test group,
+// as set by ProblemEditSolutionExtraTags. Exactly one of Testset and Group is set.
+type ExtraTag struct {
+	Solution string
+	Testset  string
+	Group    string
+	Tag      string
+}
+
+// TagCheck is the result of verifying a tag of a solution against its verdicts
+//
+// Scope      : "" for the main tag, "testset X" or "group X" for an extra tag
+//
+// Consistent : the verdicts match the tag
+//
+// Skipped    : the tag could not be verified, e.g. its testset was not judged
+//
+// Reason     : explanation of an inconsistent or skipped tag
+type TagCheck struct {
+	Solution   string
+	Tag        string
+	Scope      string
+	Consistent bool
+	Skipped    bool
+	Reason     string
+}
+
+// singleFailureTags are the tags requiring at least one test with the verdict of the same name,
+// and no other failure
+var singleFailureTags = map[string]Verdict{
+	"TL": TimeLimitExceeded,
+	"WA": WrongAnswer,
+	"PE": PresentationError,
+	"ML": MemoryLimitExceeded,
+	"RE": RuntimeError,
+}
+
+// tagConsistent checks verdicts against a polygon solution tag:
+// MA and OK accept only OK, RJ requires a failure, TO (time limit or accepted) accepts OK and TL,
+// and TL, WA, PE, ML, RE require at least one verdict of that kind and no other failure.
+func tagConsistent(tag string, verdicts []Verdict) (consistent bool, reason string) {
+	counts := make(map[Verdict]int)
+	for _, verdict := range verdicts {
+		counts[verdict]++
+	}
+	failures := len(verdicts) - counts[OK]
+
+	switch tag = strings.ToUpper(tag); tag {
+	case "MA", "OK":
+		if failures > 0 {
+			return false, "expected OK on every test, " + describeFailures(counts)
+		}
+		return true, ""
+	case "RJ":
+		if failures == 0 {
+			return false, "expected at least one failure, passed every test"
+		}
+		return true, ""
+	case "TO":
+		if failures > counts[TimeLimitExceeded] {
+			return false, "expected only OK or TL, " + describeFailures(counts)
+		}
+		return true, ""
+	}
+
+	expected, ok := singleFailureTags[tag]
+	if !ok {
+		return false, "unknown tag " + tag
+	}
+	if counts[expected] == 0 {
+		return false, "expected at least one " + string(expected) + ", " + describeFailures(counts)
+	}
+	if failures > counts[expected] {
+		return false, "expected no failure other than " + string(expected) + ", " + describeFailures(counts)
+	}
+	return true, ""
+}
+
+// describeFailures summarizes the failed verdicts
+func describeFailures(counts map[Verdict]int) string {
+	var parts []string
+	for _, verdict := range []Verdict{WrongAnswer, PresentationError, TimeLimitExceeded,
+		MemoryLimitExceeded, RuntimeError, CompilationError, Failed} {
+		if counts[verdict] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[verdict], verdict))
+		}
+	}
+	if len(parts) == 0 {
+		return "got no failure"
+	}
+	return "got " + strings.Join(parts, ", ")
+}
+
+// verdicts returns the verdicts of a solution on the tests whose group is accepted by keep
+func (solution SolutionReport) verdicts(keep func(group string) bool) (verdicts []Verdict) {
+	if solution.Verdict() == CompilationError {
+		return []Verdict{CompilationError}
+	}
+	for _, result := range solution.Tests {
+		if keep(result.Group) {
+			verdicts = append(verdicts, result.Verdict)
+		}
+	}
+	return verdicts
+}
+
+// VerifyTags checks that the main tag and the extra tags of every judged solution
+// are consistent with its verdicts.
+func VerifyTags(report Report, extraTags []ExtraTag) (checks []TagCheck) {
+	all := func(group string) bool { return true }
+
+	for _, solution := range report.Solutions {
+		check := TagCheck{Solution: solution.Solution.Name, Tag: solution.Solution.Tag}
+		check.Consistent, check.Reason = tagConsistent(check.Tag, solution.verdicts(all))
+		checks = append(checks, check)
+
+		for _, extra := range extraTags {
+			if extra.Solution != solution.Solution.Name {
+				continue
+			}
+
+			check := TagCheck{Solution: extra.Solution, Tag: extra.Tag}
+			keep := all
+			if extra.Group != "" {
+				check.Scope = "group " + extra.Group
+				keep = func(group string) bool { return group == extra.Group }
+			} else {
+				check.Scope = "testset " + extra.Testset
+				if extra.Testset != report.Testset {
+					check.Skipped, check.Reason = true, "testset "+extra.Testset+" was not judged"
+					checks = append(checks, check)
+					continue
+				}
+			}
+
+			verdicts := solution.verdicts(keep)
+			if len(verdicts) == 0 {
+				check.Skipped, check.Reason = true, "no test in "+check.Scope
+			} else {
+				check.Consistent, check.Reason = tagConsistent(check.Tag, verdicts)
+			}
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// WriteInvocationTable prints the report like polygon's invocation page:
+// one row per test, one column per solution, followed by the verdict, the tag
+// and whether the tags of each solution are consistent.
+func WriteInvocationTable(w io.Writer, report Report, checks []TagCheck) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	header := []string{"Test"}
+	for _, solution := range report.Solutions {
+		header = append(header, solution.Solution.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for row := 0; ; row++ {
+		cells, found := []string{""}, false
+		for _, solution := range report.Solutions {
+			if row >= len(solution.Tests) {
+				cells = append(cells, "")
+				continue
+			}
+			result := solution.Tests[row]
+			cells[0], found = strconv.Itoa(result.Index), true
+			cells = append(cells, fmt.Sprintf("%s %dms", result.Verdict, result.Usage.Time/time.Millisecond))
+		}
+		if !found {
+			break
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	consistent := make(map[string]bool)
+	for _, check := range checks {
+		if _, ok := consistent[check.Solution]; !ok {
+			consistent[check.Solution] = true
+		}
+		if !check.Consistent && !check.Skipped {
+			consistent[check.Solution] = false
+		}
+	}
+
+	verdicts, tags, results := []string{"Verdict"}, []string{"Tag"}, []string{"Tags"}
+	for _, solution := range report.Solutions {
+		verdicts = append(verdicts, string(solution.Verdict()))
+		tags = append(tags, solution.Solution.Tag)
+		result := "OK"
+		if !consistent[solution.Solution.Name] {
+			result = "FAIL"
+		}
+		results = append(results, result)
+	}
+	fmt.Fprintln(tw, strings.Join(verdicts, "\t"))
+	fmt.Fprintln(tw, strings.Join(tags, "\t"))
+	fmt.Fprintln(tw, strings.Join(results, "\t"))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, check := range checks {
+		if check.Consistent {
+			continue
+		}
+		scope := ""
+		if check.Scope != "" {
+			scope = " on " + check.Scope
+		}
+		status := "inconsistent"
+		if check.Skipped {
+			status = "skipped"
+		}
+		fmt.Fprintf(w, "%s: tag %s%s %s: %s\n", check.Solution, check.Tag, scope, status, check.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package judge
+
+import (
+	"testing"
+)
+
+func TestTagConsistent(t *testing.T) {
+	tests := []struct {
+		tag        string
+		verdicts   []Verdict
+		consistent bool
+	}{
+		{"MA", []Verdict{OK, OK}, true},
+		{"OK", []Verdict{OK, WrongAnswer}, false},
+		{"ok", []Verdict{OK}, true},
+		{"RJ", []Verdict{OK, RuntimeError}, true},
+		{"RJ", []Verdict{OK, OK}, false},
+		{"TO", []Verdict{OK, TimeLimitExceeded}, true},
+		{"TO", []Verdict{OK}, true},
+		{"TO", []Verdict{TimeLimitExceeded, WrongAnswer}, false},
+		{"TL", []Verdict{OK, TimeLimitExceeded}, true},
+		{"TL", []Verdict{OK, OK}, false},
+		{"TL", []Verdict{TimeLimitExceeded, MemoryLimitExceeded}, false},
+		{"WA", []Verdict{WrongAnswer, WrongAnswer}, true},
+		{"WA", []Verdict{PresentationError}, false},
+		{"PE", []Verdict{PresentationError}, true},
+		{"ML", []Verdict{MemoryLimitExceeded, OK}, true},
+		{"RE", []Verdict{RuntimeError}, true},
+		{"MA", []Verdict{CompilationError}, false},
+		{"XX", []Verdict{OK}, false},
+	}
+	for _, test := range tests {
+		consistent, reason := tagConsistent(test.tag, test.verdicts)
+		if consistent != test.consistent {
+			t.Errorf("tagConsistent(%s, %v) = %v (%s), want %v", test.tag, test.verdicts, consistent, reason, test.consistent)
+		}
+		if !consistent && reason == "" {
+			t.Errorf("tagConsistent(%s, %v) gave no reason", test.tag, test.verdicts)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package judge
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Exit codes of testlib checkers and interactors
+const (
+	testlibOK            = 0
+	testlibWrongAnswer   = 1
+	testlibPresentation  = 2
+	testlibFail          = 3
+	testlibDirt          = 4
+	testlibPoints        = 7
+	testlibUnexpectedEOF = 8
+	// testlibPartial is added to n by quitf(_pc(n), ...), which awards n points
+	testlibPartial = 50
+)
+
+// testlibVerdict returns the verdict of a testlib exit code, and whether points were reported
+func testlibVerdict(exitCode int) (verdict Verdict, points bool) {
+	switch exitCode {
+	case testlibOK:
+		return OK, false
+	case testlibWrongAnswer, testlibDirt:
+		return WrongAnswer, false
+	case testlibPresentation, testlibUnexpectedEOF:
+		return PresentationError, false
+	case testlibPoints:
+		return OK, true
+	}
+	return Failed, false
+}
+
+// parsePoints reads the points printed by quitp, e.g. "points 12.5 partial answer"
+func parsePoints(message string) (points float64, rest string, err error) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(message), "points "))
+	if len(fields) == 0 {
+		return 0, message, fmt.Errorf("judge: no points in checker output %q", message)
+	}
+	if points, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return 0, message, fmt.Errorf("judge: invalid points in checker output %q", message)
+	}
+	return points, strings.Join(fields[1:], " "), nil
+}
+
+// testlibResult converts the exit code and the output of a testlib program to a check result
+func testlibResult(usage Usage, output string) (result CheckResult, err error) {
+	result.Message = strings.TrimSpace(output)
+	if usage.Killed {
+		return CheckResult{Verdict: Failed, Message: "checker exceeded the time limit"}, nil
+	}
+
+	if usage.ExitCode >= testlibPartial {
+		result.Verdict, result.Points, result.HasPoints = OK, float64(usage.ExitCode-testlibPartial), true
+		return result, nil
+	}
+
+	var points bool
+	result.Verdict, points = testlibVerdict(usage.ExitCode)
+	if result.Verdict == Failed && usage.ExitCode != testlibFail {
+		result.Message = fmt.Sprintf("unexpected exit code %d: %s", usage.ExitCode, result.Message)
+	}
+	if points {
+		if result.Points, result.Message, err = parsePoints(result.Message); err != nil {
+			return result, err
+		}
+		result.HasPoints = true
+	}
+	return result, nil
+}
+
+// TestlibChecker runs a compiled testlib checker as "checker input output answer".
+// Exit code 0 is OK, 1 and 4 are WA, 2 and 8 are PE, 3 is FL, and 7 reports points, returned as OK
+// with CheckResult.Points set. Exit codes 50 and above, from _pc(n), are returned as OK with n points.
+type TestlibChecker struct {
+	Program *Program
+}
+
+// Check runs the checker on the output
+func (checker TestlibChecker) Check(inputPath, outputPath, answerPath string) (result CheckResult, err error) {
+	var output bytes.Buffer
+	args := []string{inputPath, outputPath, answerPath}
+	usage, err := Execute(checker.Program, args, filepath.Dir(outputPath), nil, &output, &output, toolLimits)
+	if err != nil {
+		return result, err
+	}
+	return testlibResult(usage, output.String())
+}
+
+// LoadChecker compiles the checker of the problem. Standard checkers (std::*) are not
+// available as source files, and are approximated by TokenChecker.
+func (workspace *Workspace) LoadChecker() (checker Checker, err error) {
+	name, err := workspace.client.ProblemChecker(map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	if name == "" || strings.HasPrefix(name, "std::") {
+		return TokenChecker{}, nil
+	}
+
+	program, err := workspace.CompileSourceFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return TestlibChecker{Program: program}, nil
+}
@@ -0,0 +1,40 @@
+package judge
+
+import (
+	"testing"
+)
+
+func TestTestlibResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		usage  Usage
+		output string
+		want   CheckResult
+		err    bool
+	}{
+		{"ok", Usage{ExitCode: testlibOK}, "ok 1 number\n", CheckResult{Verdict: OK, Message: "ok 1 number"}, false},
+		{"wrong answer", Usage{ExitCode: testlibWrongAnswer}, "wrong answer 1st numbers differ", CheckResult{Verdict: WrongAnswer, Message: "wrong answer 1st numbers differ"}, false},
+		{"dirt", Usage{ExitCode: testlibDirt}, "", CheckResult{Verdict: WrongAnswer}, false},
+		{"presentation", Usage{ExitCode: testlibPresentation}, "", CheckResult{Verdict: PresentationError}, false},
+		{"unexpected eof", Usage{ExitCode: testlibUnexpectedEOF}, "", CheckResult{Verdict: PresentationError}, false},
+		{"fail", Usage{ExitCode: testlibFail}, "FAIL bad answer", CheckResult{Verdict: Failed, Message: "FAIL bad answer"}, false},
+		{"unknown code", Usage{ExitCode: 5}, "boom", CheckResult{Verdict: Failed, Message: "unexpected exit code 5: boom"}, false},
+		{"killed", Usage{Killed: true, ExitCode: -1}, "", CheckResult{Verdict: Failed, Message: "checker exceeded the time limit"}, false},
+		{"points", Usage{ExitCode: testlibPoints}, "points 12.5 partial\n", CheckResult{Verdict: OK, Message: "partial", Points: 12.5, HasPoints: true}, false},
+		{"invalid points", Usage{ExitCode: testlibPoints}, "points many", CheckResult{}, true},
+		{"partial", Usage{ExitCode: testlibPartial + 30}, "partially correct", CheckResult{Verdict: OK, Message: "partially correct", Points: 30, HasPoints: true}, false},
+		{"partial zero", Usage{ExitCode: testlibPartial}, "", CheckResult{Verdict: OK, HasPoints: true}, false},
+	}
+	for _, test := range tests {
+		got, err := testlibResult(test.usage, test.output)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: got %+v, want an error", test.name, got)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%s: testlibResult() = %+v, %v, want %+v", test.name, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package judge
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/variety-jones/polygon"
+)
+
+// toolLimits are the limits of validators, checkers and interactors
+var toolLimits = Limits{Time: 10 * time.Second}
+
+// ValidationResult is the outcome of the validator on a test
+type ValidationResult struct {
+	Index   int
+	Group   string
+	Valid   bool
+	Message string
+}
+
+// CompileSourceFile fetches a source file of the problem, such as the validator or the checker,
+// and compiles it together with the header files of the resources (testlib.h).
+func (workspace *Workspace) CompileSourceFile(name string) (program *Program, err error) {
+	files, err := workspace.client.ProblemFiles(map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	var file *polygon.FileObject
+	for i := range files.SourceFiles {
+		if files.SourceFiles[i].Name == name {
+			file = &files.SourceFiles[i]
+		}
+	}
+	if file == nil {
+		return nil, fmt.Errorf("judge: source file %s not found", name)
+	}
+
+	content, err := workspace.client.ProblemViewFile(map[string]string{"type": "source", "name": name})
+	if err != nil {
+		return nil, err
+	}
+	headers, err := FetchHeaders(workspace.client)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(workspace.Dir, "programs", name)
+	source := Source{Name: name, Type: file.SourceType, Content: content, Includes: headers}
+	return Compile(dir, workspace.config.Languages, source, workspace.config.CompileTimeout)
+}
+
+// Validate runs a validator on the input of a test. Like polygon, the testset and
+// the group of the test are passed with --testset and --group.
+func (workspace *Workspace) Validate(validator *Program, test Test) (result ValidationResult, err error) {
+	result.Index, result.Group = test.Index, test.Groups
+	args := []string{"--testset", workspace.Testset}
+	if test.Groups != "" {
+		args = append(args, "--group", test.Groups)
+	}
+
+	input, err := os.Open(test.InputPath)
+	if err != nil {
+		return result, err
+	}
+	defer input.Close()
+
+	var output bytes.Buffer
+	usage, err := Execute(validator, args, filepath.Dir(test.InputPath), input, &output, &output, toolLimits)
+	if err != nil {
+		return result, err
+	}
+	switch {
+	case usage.Killed:
+		result.Message = "validator exceeded the time limit"
+	case usage.ExitCode != 0:
+		result.Message = strings.TrimSpace(output.String())
+	default:
+		result.Valid = true
+	}
+	return result, nil
+}
+
+// ValidateTests compiles the validator of the problem and runs it on the input of every test
+// of the testset, returning one result per test.
+func ValidateTests(client polygon.ProblemReader, config Config) (results []ValidationResult, err error) {
+	workspace, err := NewWorkspace(client, config)
+	if err != nil {
+		return results, err
+	}
+	defer workspace.Close()
+
+	name, err := client.ProblemValidator(map[string]string{})
+	if err != nil {
+		return results, err
+	}
+	if name == "" {
+		return results, fmt.Errorf("judge: the problem has no validator")
+	}
+	validator, err := workspace.CompileSourceFile(name)
+	if err != nil {
+		return results, err
+	}
+
+	for _, test := range workspace.Tests {
+		result, err := workspace.Validate(validator, test)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,226 @@
+package kattis
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/judge"
+)
+
+// problemName returns the name of the problem in english, or in the first language available
+func problemName(statements map[string]polygon.StatementObject) string {
+	if statement, ok := statements["english"]; ok {
+		return statement.Name
+	}
+	languages := make([]string, 0, len(statements))
+	for language := range statements {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	if len(languages) == 0 {
+		return ""
+	}
+	return statements[languages[0]].Name
+}
+
+// problemYAML renders problem.yaml
+func problemYAML(name, validation, validatorFlags string, info polygon.ProblemInfoObject) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %s\n", quoteYAML(name))
+	fmt.Fprintf(&b, "validation: %s\n", validation)
+	if validatorFlags != "" {
+		fmt.Fprintf(&b, "validator_flags: %s\n", quoteYAML(validatorFlags))
+	}
+	b.WriteString("limits:\n")
+	fmt.Fprintf(&b, "  time_limit: %s\n", strconv.FormatFloat(float64(info.TimeLimit)/1000, 'f', -1, 64))
+	fmt.Fprintf(&b, "  memory: %d\n", info.MemoryLimit)
+	return b.String()
+}
+
+// texEscaper escapes the characters which are special in LaTeX text
+var texEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`#`, `\#`,
+	`$`, `\$`,
+	`{`, `\{`,
+	`}`, `\}`,
+)
+
+// statementTeX renders a statement in the problemtools LaTeX format.
+// The name is plain text and is escaped, the sections are already LaTeX.
+func statementTeX(statement polygon.StatementObject) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\\problemname{%s}\n\n", texEscaper.Replace(statement.Name))
+	b.WriteString(strings.TrimSpace(statement.Legend) + "\n")
+	for _, section := range []struct{ title, content string }{
+		{"Input", statement.Input},
+		{"Output", statement.Output},
+		{"Scoring", statement.Scoring},
+		{"Notes", statement.Notes},
+	} {
+		if strings.TrimSpace(section.content) != "" {
+			fmt.Fprintf(&b, "\n\\section*{%s}\n%s\n", section.title, strings.TrimSpace(section.content))
+		}
+	}
+	return b.String()
+}
+
+// exportSource writes a source file of the problem with the header files of its resources
+// to its own directory, e.g. output_validators/check/check.cpp
+func exportSource(client polygon.ProblemReader, dir, parent, name string, headers map[string]string) error {
+	content, err := client.ProblemViewFile(map[string]string{"type": "source", "name": name})
+	if err != nil {
+		return err
+	}
+	directory := path.Join(parent, strings.TrimSuffix(name, path.Ext(name)))
+	for header, headerContent := range headers {
+		if err = writeFile(dir, path.Join(directory, header), []byte(headerContent)); err != nil {
+			return err
+		}
+	}
+	return writeFile(dir, path.Join(directory, name), []byte(content))
+}
+
+// exportTests writes the tests of the testset, tests used in the statements being samples.
+// Indices are padded with zeros to the width of the largest one, and at least two digits,
+// so that the files sort in the order of the tests.
+func exportTests(client polygon.ProblemReader, dir, testset string) error {
+	tests, err := client.ProblemTests(map[string]string{"testset": testset, "noInputs": "true"})
+	if err != nil {
+		return err
+	}
+	width := 2
+	for _, test := range tests {
+		if digits := len(strconv.Itoa(test.Index)); digits > width {
+			width = digits
+		}
+	}
+	for _, test := range tests {
+		parameters := map[string]string{"testset": testset, "testIndex": strconv.Itoa(test.Index)}
+		input, err := client.ProblemTestInput(parameters)
+		if err != nil {
+			return err
+		}
+		answer, err := client.ProblemTestAnswer(parameters)
+		if err != nil {
+			return err
+		}
+
+		name := fmt.Sprintf("%0*d", width, test.Index)
+		base := path.Join("data", "secret", name)
+		if test.UseInStatements {
+			base = path.Join("data", "sample", name)
+		}
+		if err = writeFile(dir, base+".in", []byte(input)); err != nil {
+			return err
+		}
+		if err = writeFile(dir, base+".ans", []byte(answer)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Export writes the problem as a Kattis package in dir: its limits, statements, tests,
+// validator, checker or interactor, and its solutions sorted by tag. Standard checkers become
+// the default output validator. Validators, checkers and interactors are copied as is with the
+// header files of the problem, they use the conventions of testlib and must be adapted
+// to the Kattis ones (exit codes 42 and 43, feedback directory) before being used.
+//
+// The names of the solutions whose tag has no submission directory (RJ and TO) are returned.
+func Export(client polygon.ProblemReader, dir string, config Config) (skipped []string, err error) {
+	info, err := client.ProblemInfo(map[string]string{})
+	if err != nil {
+		return skipped, err
+	}
+	statements, err := client.ProblemStatements(map[string]string{})
+	if err != nil {
+		return skipped, err
+	}
+	headers, err := judge.FetchHeaders(client)
+	if err != nil {
+		return skipped, err
+	}
+
+	checker, err := client.ProblemChecker(map[string]string{})
+	if err != nil {
+		return skipped, err
+	}
+	validation, validatorFlags := "default", ""
+	switch {
+	case info.Interactive:
+		interactor, err := client.ProblemInteractor(map[string]string{})
+		if err != nil {
+			return skipped, err
+		}
+		validation = "custom interactive"
+		if err = exportSource(client, dir, "output_validators", interactor, headers); err != nil {
+			return skipped, err
+		}
+	case strings.HasPrefix(checker, "std::"):
+		validatorFlags = standardValidatorFlags[checker]
+	case checker != "":
+		validation = "custom"
+		if err = exportSource(client, dir, "output_validators", checker, headers); err != nil {
+			return skipped, err
+		}
+	}
+
+	yaml := problemYAML(problemName(statements), validation, validatorFlags, info)
+	if err = writeFile(dir, "problem.yaml", []byte(yaml)); err != nil {
+		return skipped, err
+	}
+	for language, statement := range statements {
+		code, ok := languageCodes[language]
+		if !ok {
+			code = language
+		}
+		name := path.Join("problem_statement", "problem."+code+".tex")
+		if err = writeFile(dir, name, []byte(statementTeX(statement))); err != nil {
+			return skipped, err
+		}
+	}
+
+	if err = exportTests(client, dir, config.testset()); err != nil {
+		return skipped, err
+	}
+
+	validator, err := client.ProblemValidator(map[string]string{})
+	if err != nil {
+		return skipped, err
+	}
+	if validator != "" {
+		if err = exportSource(client, dir, "input_validators", validator, headers); err != nil {
+			return skipped, err
+		}
+	}
+
+	solutions, err := client.ProblemSolutions(map[string]string{})
+	if err != nil {
+		return skipped, err
+	}
+	for _, solution := range solutions {
+		directory, ok := submissionDirectories[strings.ToUpper(solution.Tag)]
+		if !ok {
+			skipped = append(skipped, solution.Name)
+			continue
+		}
+		source, err := client.ProblemViewSolution(map[string]string{"name": solution.Name})
+		if err != nil {
+			return skipped, err
+		}
+		if err = writeFile(dir, path.Join("submissions", directory, solution.Name), []byte(source)); err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
@@ -0,0 +1,347 @@
+package kattis
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+)
+
+// submissionTags maps the submission directories to polygon solution tags.
+// The first accepted submission becomes the main solution (MA).
+var submissionTags = map[string]string{
+	"accepted":            "OK",
+	"wrong_answer":        "WA",
+	"time_limit_exceeded": "TL",
+	"run_time_error":      "RE",
+}
+
+// sourceTypes maps file extensions to polygon source types
+var sourceTypes = map[string]string{
+	".c":    "c.gcc",
+	".cc":   "cpp.g++17",
+	".cpp":  "cpp.g++17",
+	".cxx":  "cpp.g++17",
+	".java": "java11",
+	".kt":   "kotlin",
+	".pas":  "pascal.fpc",
+	".py":   "python.3",
+}
+
+// statementSection matches the sections of a problemtools statement
+var statementSection = regexp.MustCompile(`\\section\*?\{([^}]*)\}`)
+
+// problemNameCommand matches the name of a problemtools statement
+var problemNameCommand = regexp.MustCompile(`\\problemname\{([^}]*)\}`)
+
+// parseStatementTeX splits a problemtools statement into the parameters of ProblemSaveStatement
+func parseStatementTeX(tex string) map[string]string {
+	parameters := map[string]string{}
+	if match := problemNameCommand.FindStringSubmatch(tex); match != nil {
+		parameters["name"] = strings.TrimSpace(match[1])
+		tex = strings.Replace(tex, match[0], "", 1)
+	}
+
+	sections := map[string]string{"input": "input", "output": "output", "notes": "notes", "note": "notes", "scoring": "scoring"}
+	key, start := "legend", 0
+	for _, match := range statementSection.FindAllStringSubmatchIndex(tex, -1) {
+		parameters[key] += strings.TrimSpace(tex[start:match[0]])
+		title := strings.ToLower(strings.TrimSpace(tex[match[2]:match[3]]))
+		if section, ok := sections[title]; ok {
+			key, start = section, match[1]
+		} else {
+			// Unknown sections stay in the current part of the statement
+			start = match[0]
+			parameters[key] += "\n\n"
+		}
+	}
+	parameters[key] += strings.TrimSpace(tex[start:])
+	return parameters
+}
+
+// importInfo sets the limits of the problem from problem.yaml, or .timelimit for the time limit
+func importInfo(client polygon.ProblemWriter, dir string, document map[string]interface{}) error {
+	parameters := map[string]string{
+		"interactive": strconv.FormatBool(strings.Contains(yamlString(document, "validation"), "interactive")),
+	}
+
+	timeLimit := yamlString(document, "limits", "time_limit")
+	if timeLimit == "" {
+		if content, err := ioutil.ReadFile(filepath.Join(dir, ".timelimit")); err == nil {
+			timeLimit = strings.TrimSpace(string(content))
+		}
+	}
+	if timeLimit != "" {
+		seconds, err := strconv.ParseFloat(timeLimit, 64)
+		if err != nil {
+			return err
+		}
+		parameters["timeLimit"] = strconv.Itoa(int(math.Round(seconds * 1000)))
+	}
+	if memory := yamlString(document, "limits", "memory"); memory != "" {
+		parameters["memoryLimit"] = memory
+	}
+	return client.ProblemUpdateInfo(parameters)
+}
+
+// importStatements saves problem_statement/problem.<lang>.tex, problem.tex being english
+func importStatements(client polygon.ProblemWriter, dir, name string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "problem_statement", "problem*.tex"))
+	if err != nil {
+		return err
+	}
+
+	languages := map[string]string{"": "english"}
+	for language, code := range languageCodes {
+		languages[code] = language
+	}
+	for _, path := range paths {
+		code := strings.TrimPrefix(strings.TrimSuffix(filepath.Base(path), ".tex"), "problem")
+		language, ok := languages[strings.TrimPrefix(code, ".")]
+		if !ok {
+			language = strings.TrimPrefix(code, ".")
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		parameters := parseStatementTeX(string(content))
+		if parameters["name"] == "" {
+			parameters["name"] = name
+		}
+		parameters["lang"], parameters["encoding"] = language, "UTF-8"
+		if err = client.ProblemSaveStatement(parameters); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testInputs returns the .in files of a data directory and its subdirectories, sorted by path
+func testInputs(dir string) (paths []string, err error) {
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		}
+		if err == nil && !info.IsDir() && filepath.Ext(path) == ".in" {
+			paths = append(paths, path)
+		}
+		return err
+	})
+	sort.Strings(paths)
+	return paths, err
+}
+
+// importTests saves the samples, used in the statements, followed by the secret tests.
+// Answers are not uploaded, polygon generates them with the main solution.
+func importTests(client polygon.ProblemWriter, dir, testset string) error {
+	index := 0
+	for _, data := range []string{"sample", "secret"} {
+		paths, err := testInputs(filepath.Join(dir, "data", data))
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			input, err := ioutil.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			index++
+			err = client.ProblemSaveTest(map[string]string{
+				"testset":             testset,
+				"testIndex":           strconv.Itoa(index),
+				"testInput":           string(input),
+				"testUseInStatements": strconv.FormatBool(data == "sample"),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// importSources saves the source files found in a validator directory, such as
+// input_validators or output_validators, and returns the name of the first one.
+// Header files other than testlib.h, which polygon provides, are saved as resources.
+func importSources(client polygon.ProblemWriter, dir string) (first string, err error) {
+	var paths []string
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return filepath.SkipDir
+		}
+		if err == nil && !info.IsDir() {
+			paths = append(paths, path)
+		}
+		return err
+	})
+	if err != nil {
+		return first, err
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+		extension := filepath.Ext(name)
+		sourceType, isSource := sourceTypes[extension]
+		isHeader := (extension == ".h" || extension == ".hpp") && name != "testlib.h"
+		if !isSource && !isHeader {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return first, err
+		}
+		parameters := map[string]string{"type": "resource", "name": name, "file": string(content)}
+		if isSource {
+			parameters["type"], parameters["sourceType"] = "source", sourceType
+		}
+		if err = client.ProblemSaveFile(parameters); err != nil {
+			return first, err
+		}
+		if isSource && first == "" {
+			first = name
+		}
+	}
+	return first, nil
+}
+
+// importValidators saves the input validator, and the output validator as the checker,
+// or the interactor of an interactive problem. The default output validator becomes
+// the standard checker matching its flags.
+func importValidators(client polygon.ProblemWriter, dir string, document map[string]interface{}) error {
+	validator, err := importSources(client, filepath.Join(dir, "input_validators"))
+	if err != nil {
+		return err
+	}
+	if validator != "" {
+		if err = client.ProblemSetValidator(map[string]string{"validator": validator}); err != nil {
+			return err
+		}
+	}
+
+	validation := yamlString(document, "validation")
+	if validation == "" || validation == "default" {
+		checker := "std::wcmp.cpp"
+		for name, flags := range standardValidatorFlags {
+			if flags == yamlString(document, "validator_flags") {
+				checker = name
+			}
+		}
+		return client.ProblemSetChecker(map[string]string{"checker": checker})
+	}
+
+	outputValidator, err := importSources(client, filepath.Join(dir, "output_validators"))
+	if err != nil || outputValidator == "" {
+		return err
+	}
+	if strings.Contains(validation, "interactive") {
+		return client.ProblemSetInteractor(map[string]string{"interactor": outputValidator})
+	}
+	return client.ProblemSetChecker(map[string]string{"checker": outputValidator})
+}
+
+// importSubmissions saves the submissions as solutions tagged after their directory.
+// A submission named like one of a previous directory is saved with the directory as prefix,
+// e.g. wrong_answer_sol.cpp. Submissions which are not a single file in a known language are returned.
+func importSubmissions(client polygon.ProblemWriter, dir string) (skipped []string, err error) {
+	directories := make([]string, 0, len(submissionTags))
+	for directory := range submissionTags {
+		directories = append(directories, directory)
+	}
+	sort.Strings(directories)
+
+	mainSaved := false
+	names := make(map[string]bool)
+	for _, directory := range directories {
+		entries, err := ioutil.ReadDir(filepath.Join(dir, "submissions", directory))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return skipped, err
+		}
+
+		for _, entry := range entries {
+			relative := filepath.Join(directory, entry.Name())
+			sourceType, ok := sourceTypes[filepath.Ext(entry.Name())]
+			if entry.IsDir() || !ok {
+				skipped = append(skipped, relative)
+				continue
+			}
+			content, err := ioutil.ReadFile(filepath.Join(dir, "submissions", relative))
+			if err != nil {
+				return skipped, err
+			}
+
+			name := entry.Name()
+			if names[name] {
+				name = directory + "_" + name
+			}
+			if names[name] {
+				skipped = append(skipped, relative)
+				continue
+			}
+			names[name] = true
+
+			tag := submissionTags[directory]
+			if tag == "OK" && !mainSaved {
+				tag, mainSaved = "MA", true
+			}
+			err = client.ProblemSaveSolution(map[string]string{
+				"name":       name,
+				"file":       string(content),
+				"sourceType": sourceType,
+				"tag":        tag,
+			})
+			if err != nil {
+				return skipped, err
+			}
+		}
+	}
+	return skipped, nil
+}
+
+// Import creates or updates the problem of the client from the Kattis package in dir:
+// limits, statements, tests, validators and submissions. Tests are uploaded without their
+// answers, which polygon generates with the main solution, the first accepted submission.
+// Validators are uploaded as is, and have to be adapted to testlib.
+//
+// The submissions which could not be imported, such as multi-file submissions, are returned
+// relative to the submissions directory.
+func Import(client polygon.ProblemWriter, dir string, config Config) (skipped []string, err error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, "problem.yaml"))
+	if err != nil {
+		return skipped, err
+	}
+	document, err := parseYAML(string(content))
+	if err != nil {
+		return skipped, err
+	}
+
+	if err = importInfo(client, dir, document); err != nil {
+		return skipped, err
+	}
+	name := yamlString(document, "name")
+	if name == "" {
+		name = filepath.Base(dir)
+	}
+	if err = importStatements(client, dir, name); err != nil {
+		return skipped, err
+	}
+	if err = importTests(client, dir, config.testset()); err != nil {
+		return skipped, err
+	}
+	if err = importValidators(client, dir, document); err != nil {
+		return skipped, err
+	}
+	return importSubmissions(client, dir)
+}
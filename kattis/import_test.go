@@ -0,0 +1,44 @@
+package kattis
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/variety-jones/polygon/polygonmock"
+)
+
+func TestImportSubmissionsKeepsSameNames(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kattis")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"accepted/sol.cpp", "wrong_answer/sol.cpp", "wrong_answer/sol.txt"} {
+		if err = writeFile(dir, "submissions/"+name, []byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saved := make(map[string]string)
+	client := &polygonmock.Client{ProblemSaveSolutionFunc: func(parameters map[string]string) error {
+		saved[parameters["name"]] = parameters["tag"] + " " + parameters["file"]
+		return nil
+	}}
+	skipped, err := importSubmissions(client, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"sol.cpp":              "MA accepted/sol.cpp",
+		"wrong_answer_sol.cpp": "WA wrong_answer/sol.cpp",
+	}
+	if !reflect.DeepEqual(saved, want) {
+		t.Errorf("saved %v, want %v", saved, want)
+	}
+	if !reflect.DeepEqual(skipped, []string{filepath.Join("wrong_answer", "sol.txt")}) {
+		t.Errorf("skipped %v, want wrong_answer/sol.txt", skipped)
+	}
+}
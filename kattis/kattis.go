@@ -0,0 +1,98 @@
+// Package kattis converts polygon problems to and from the Kattis problem package format,
+// also used by DOMjudge and the ICPC (https://www.kattis.com/problem-package-format/).
+//
+// A package is a directory holding
+//
+//	problem.yaml
+//	problem_statement/problem.<lang>.tex
+//	data/sample/*.in, *.ans
+//	data/secret/*.in, *.ans
+//	input_validators/<name>/
+//	output_validators/<name>/
+//	submissions/accepted|wrong_answer|time_limit_exceeded|run_time_error/
+package kattis
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Config holds the settings of Export and Import
+//
+// Testset : polygon testset holding the tests, defaults to "tests"
+type Config struct {
+	Testset string
+}
+
+// testset returns the configured testset or the default one
+func (config Config) testset() string {
+	if config.Testset == "" {
+		return "tests"
+	}
+	return config.Testset
+}
+
+// submissionDirectories maps polygon solution tags to the submission directories.
+// RJ (any failure) and TO (time limit or accepted) have no equivalent.
+var submissionDirectories = map[string]string{
+	"MA": "accepted",
+	"OK": "accepted",
+	"WA": "wrong_answer",
+	"PE": "wrong_answer",
+	"TL": "time_limit_exceeded",
+	"ML": "run_time_error",
+	"RE": "run_time_error",
+}
+
+// languageCodes maps polygon statement languages to the codes of statement file names
+var languageCodes = map[string]string{
+	"english":    "en",
+	"russian":    "ru",
+	"ukrainian":  "uk",
+	"french":     "fr",
+	"german":     "de",
+	"spanish":    "es",
+	"portuguese": "pt",
+	"italian":    "it",
+	"polish":     "pl",
+	"chinese":    "zh",
+	"japanese":   "ja",
+	"korean":     "ko",
+}
+
+// standardValidatorFlags maps polygon standard checkers to the flags of the default output validator.
+// Other standard checkers compare tokens, as the default output validator does without flags.
+var standardValidatorFlags = map[string]string{
+	"std::rcmp4.cpp": "float_tolerance 1e-4",
+	"std::rcmp6.cpp": "float_tolerance 1e-6",
+	"std::rcmp9.cpp": "float_tolerance 1e-9",
+	"std::yesno.cpp": "case_insensitive",
+}
+
+// yamlKeywords are plain scalars which YAML does not read as strings
+var yamlKeywords = map[string]bool{
+	"~": true, "null": true, "true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+}
+
+// quoteYAML returns a YAML scalar holding value, quoted when it would not be read back as this string
+func quoteYAML(value string) string {
+	_, numberErr := strconv.ParseFloat(value, 64)
+	if value == "" || numberErr == nil || yamlKeywords[strings.ToLower(value)] ||
+		strings.ContainsAny(value, ":#'\"{}[],&*!|>%@`\n\\") ||
+		strings.TrimSpace(value) != value || value != strings.Trim(value, "-?") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// writeFile writes a file of the package, creating its directory
+func writeFile(dir, name string, content []byte) error {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, content, 0644)
+}
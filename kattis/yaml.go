@@ -0,0 +1,148 @@
+package kattis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// yamlLine is a significant line of a YAML document
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// parseYAML reads the subset of YAML used by problem.yaml: nested block mappings,
+// block sequences and scalars. Flow collections ([a, b] and {a: b}) and compact mappings
+// in sequences (- key: value) are kept as strings.
+// Mappings are returned as map[string]interface{}, sequences as []interface{}, and scalars as strings.
+func parseYAML(source string) (document map[string]interface{}, err error) {
+	var lines []yamlLine
+	for number, text := range strings.Split(source, "\n") {
+		text = stripComment(strings.TrimRight(text, " \t\r"))
+		if strings.TrimSpace(text) == "" || text == "---" {
+			continue
+		}
+		indent := len(text) - len(strings.TrimLeft(text, " "))
+		lines = append(lines, yamlLine{number: number + 1, indent: indent, text: text[indent:]})
+	}
+
+	value, rest, err := parseYAMLBlock(lines, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("kattis: yaml line %d: unexpected indentation", rest[0].number)
+	}
+	document, ok := value.(map[string]interface{})
+	if !ok && value != nil {
+		return nil, fmt.Errorf("kattis: yaml document is not a mapping")
+	}
+	return document, nil
+}
+
+// stripComment removes a comment outside of quotes, skipping escaped characters in double quotes
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+// parseYAMLBlock parses the mapping or sequence starting at the first line, and the following
+// lines of the same indentation. It returns the lines after the block.
+func parseYAMLBlock(lines []yamlLine, indent int) (value interface{}, rest []yamlLine, err error) {
+	if len(lines) == 0 || lines[0].indent < indent {
+		return nil, lines, nil
+	}
+	indent = lines[0].indent
+
+	if strings.HasPrefix(lines[0].text, "- ") || lines[0].text == "-" {
+		var sequence []interface{}
+		for len(lines) > 0 && lines[0].indent == indent && strings.HasPrefix(lines[0].text+" ", "- ") {
+			item := strings.TrimSpace(strings.TrimPrefix(lines[0].text, "-"))
+			if item != "" {
+				sequence = append(sequence, parseYAMLScalar(item))
+				lines = lines[1:]
+				continue
+			}
+			value, lines, err = parseYAMLBlock(lines[1:], indent+1)
+			if err != nil {
+				return nil, lines, err
+			}
+			sequence = append(sequence, value)
+		}
+		return sequence, lines, nil
+	}
+
+	mapping := make(map[string]interface{})
+	for len(lines) > 0 && lines[0].indent == indent {
+		line := lines[0]
+		separator := strings.Index(line.text+" ", ": ")
+		if separator < 0 {
+			return nil, lines, fmt.Errorf("kattis: yaml line %d: expected \"key: value\"", line.number)
+		}
+		key := parseYAMLScalar(strings.TrimSpace(line.text[:separator]))
+		item := strings.TrimSpace(line.text[separator+1:])
+		if item != "" {
+			mapping[key] = parseYAMLScalar(item)
+			lines = lines[1:]
+			continue
+		}
+		// A sequence may be indented like its key
+		childIndent := indent + 1
+		if len(lines) > 1 && lines[1].indent == indent && strings.HasPrefix(lines[1].text+" ", "- ") {
+			childIndent = indent
+		}
+		var value interface{}
+		if value, lines, err = parseYAMLBlock(lines[1:], childIndent); err != nil {
+			return nil, lines, err
+		}
+		mapping[key] = value
+	}
+	if len(lines) > 0 && lines[0].indent > indent {
+		return nil, lines, fmt.Errorf("kattis: yaml line %d: unexpected indentation", lines[0].number)
+	}
+	return mapping, lines, nil
+}
+
+// parseYAMLScalar unquotes a scalar
+func parseYAMLScalar(text string) string {
+	switch {
+	case len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"':
+		if unquoted, err := strconv.Unquote(text); err == nil {
+			return unquoted
+		}
+	case len(text) >= 2 && text[0] == '\'' && text[len(text)-1] == '\'':
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'")
+	}
+	return text
+}
+
+// yamlString returns the scalar at a path of keys, or "" if there is none
+func yamlString(document map[string]interface{}, keys ...string) string {
+	var value interface{} = document
+	for _, key := range keys {
+		mapping, ok := value.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		value = mapping[key]
+	}
+	scalar, _ := value.(string)
+	return scalar
+}
@@ -0,0 +1,104 @@
+package kattis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   map[string]interface{}
+		err    bool
+	}{
+		{
+			name:   "scalars",
+			source: "---\nname: Hello\nvalidation: custom interactive # comment\n# full line comment\n",
+			want:   map[string]interface{}{"name": "Hello", "validation": "custom interactive"},
+		},
+		{
+			name:   "quoted",
+			source: "name: \"a # b\"\nsource: 'it''s # here'\nurl: a#b\n",
+			want:   map[string]interface{}{"name": "a # b", "source": "it's # here", "url": "a#b"},
+		},
+		{
+			name:   "escaped quote",
+			source: `name: "a\" #b" # comment`,
+			want:   map[string]interface{}{"name": `a" #b`},
+		},
+		{
+			name:   "escaped backslash",
+			source: `name: "a\\" #b`,
+			want:   map[string]interface{}{"name": `a\`},
+		},
+		{
+			name:   "nested",
+			source: "limits:\n  time_limit: 2\n  memory: 256\nkeywords:\n- graphs\n- trees\n",
+			want: map[string]interface{}{
+				"limits":   map[string]interface{}{"time_limit": "2", "memory": "256"},
+				"keywords": []interface{}{"graphs", "trees"},
+			},
+		},
+		{
+			name:   "indented sequence",
+			source: "keywords:\n  - graphs\n",
+			want:   map[string]interface{}{"keywords": []interface{}{"graphs"}},
+		},
+		{name: "missing separator", source: "name\n", err: true},
+		{name: "unexpected indentation", source: "name: a\n    memory: 1\n", err: true},
+	}
+	for _, test := range tests {
+		document, err := parseYAML(test.source)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: got %v, want an error", test.name, document)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(document, test.want) {
+			t.Errorf("%s: got %#v, want %#v", test.name, document, test.want)
+		}
+	}
+}
+
+func TestQuoteYAML(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Hello World", "Hello World"},
+		{"", `""`},
+		{"42", `"42"`},
+		{"true", `"true"`},
+		{"No", `"No"`},
+		{"a: b", `"a: b"`},
+		{"a # b", `"a # b"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{" padded", `" padded"`},
+		{"-flag", `"-flag"`},
+		{"line\nbreak", `"line\nbreak"`},
+	}
+	for _, test := range tests {
+		if got := quoteYAML(test.value); got != test.want {
+			t.Errorf("quoteYAML(%q) = %s, want %s", test.value, got, test.want)
+		}
+	}
+}
+
+func TestQuoteYAMLRoundTrip(t *testing.T) {
+	for _, value := range []string{"Hello", "", "a: b", `a" #b`, `back\slash #`, "it's", "null", "-1.5e3"} {
+		document, err := parseYAML("name: " + quoteYAML(value) + " # comment\n")
+		if err != nil {
+			t.Errorf("%q: %v", value, err)
+			continue
+		}
+		if got := yamlString(document, "name"); got != value {
+			t.Errorf("%q quoted as %s parses to %q", value, quoteYAML(value), got)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// Package lint checks a polygon problem for common mistakes, such as a missing main solution,
+// checker or validator, misconfigured test groups and points, or missing tutorials.
+// Issues can be written as JSON for continuous integration.
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+)
+
+// Lint rules, as reported in Issue.Rule
+const (
+	RuleMainSolution = "main-solution"
+	RuleValidator    = "validator"
+	RuleChecker      = "checker"
+	RuleSamples      = "samples"
+	RuleGroups       = "groups"
+	RulePoints       = "points"
+	RuleDependencies = "dependencies"
+	RuleTutorial     = "tutorial"
+	RuleTags         = "tags"
+)
+
+// Severity is the level of a rule
+type Severity string
+
+// Severities of the rules, Off disabling a rule
+const (
+	Error   Severity = "error"
+	Warning Severity = "warning"
+	Off     Severity = "off"
+)
+
+// DefaultSeverities are the severities of the rules which are not configured
+var DefaultSeverities = map[string]Severity{
+	RuleMainSolution: Error,
+	RuleValidator:    Error,
+	RuleChecker:      Error,
+	RuleSamples:      Error,
+	RuleGroups:       Error,
+	RulePoints:       Error,
+	RuleDependencies: Error,
+	RuleTutorial:     Warning,
+	RuleTags:         Warning,
+}
+
+// Issue is a mistake found in a problem by Lint
+//
+// Rule     : one of the Rule* constants
+//
+// Severity : severity of the rule
+//
+// Message  : human readable explanation
+type Issue struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// String returns the issue as "severity: rule: message"
+func (issue Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", issue.Severity, issue.Rule, issue.Message)
+}
+
+// Config holds the settings of Lint
+//
+// Testset  : testset whose tests are checked, defaults to "tests"
+//
+// Language : language of the statement holding the samples and the tutorial, defaults to "english"
+//
+// Rules    : severity of the rules, overriding DefaultSeverities
+type Config struct {
+	Testset  string
+	Language string
+	Rules    map[string]Severity
+}
+
+// severity returns the configured severity of a rule
+func (config Config) severity(rule string) Severity {
+	if severity, ok := config.Rules[rule]; ok {
+		return severity
+	}
+	return DefaultSeverities[rule]
+}
+
+// problem holds what the rules check
+type problem struct {
+	solutions  []polygon.SolutionObject
+	validator  string
+	checker    string
+	tests      []polygon.TestObject
+	groups     []polygon.TestGroupObject
+	statements map[string]polygon.StatementObject
+	tutorial   string
+	tags       []string
+}
+
+// load reads the problem, stopping early when the context is done
+func load(ctx context.Context, client polygon.ProblemReader, config Config) (p problem, err error) {
+	testset := map[string]string{"testset": config.Testset}
+	steps := []func() error{
+		func() (err error) { p.solutions, err = client.ProblemSolutions(map[string]string{}); return },
+		func() (err error) { p.validator, err = client.ProblemValidator(map[string]string{}); return },
+		func() (err error) { p.checker, err = client.ProblemChecker(map[string]string{}); return },
+		func() (err error) {
+			p.tests, err = client.ProblemTests(map[string]string{"testset": config.Testset, "noInputs": "true"})
+			return
+		},
+		func() (err error) {
+			for _, test := range p.tests {
+				if test.Groups != "" {
+					p.groups, err = client.ProblemViewTestGroup(testset)
+					return
+				}
+			}
+			return nil
+		},
+		func() (err error) { p.statements, err = client.ProblemStatements(map[string]string{}); return },
+		func() (err error) { p.tutorial, err = client.ProblemViewGeneralTutorial(map[string]string{}); return },
+		func() (err error) { p.tags, err = client.ProblemViewTags(map[string]string{}); return },
+	}
+	for _, step := range steps {
+		if err = ctx.Err(); err != nil {
+			return p, err
+		}
+		if err = step(); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
+
+// checks maps the rules to their check, returning the messages of the issues found
+var checks = map[string]func(p problem, config Config) []string{
+	RuleMainSolution: checkMainSolution,
+	RuleValidator:    checkValidator,
+	RuleChecker:      checkChecker,
+	RuleSamples:      checkSamples,
+	RuleGroups:       checkGroups,
+	RulePoints:       checkPoints,
+	RuleDependencies: checkDependencies,
+	RuleTutorial:     checkTutorial,
+	RuleTags:         checkTags,
+}
+
+// Rules returns the names of every rule, sorted
+func Rules() []string {
+	rules := make([]string, 0, len(checks))
+	for rule := range checks {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// checkMainSolution reports problems without exactly one main solution
+func checkMainSolution(p problem, config Config) []string {
+	count := 0
+	for _, solution := range p.solutions {
+		if solution.Tag == "MA" {
+			count++
+		}
+	}
+	if count != 1 {
+		return []string{fmt.Sprintf("%d main solutions, expected 1", count)}
+	}
+	return nil
+}
+
+// checkValidator reports problems without validator
+func checkValidator(p problem, config Config) []string {
+	if p.validator == "" {
+		return []string{"no validator is set"}
+	}
+	return nil
+}
+
+// checkChecker reports problems without checker
+func checkChecker(p problem, config Config) []string {
+	if p.checker == "" {
+		return []string{"no checker is set"}
+	}
+	return nil
+}
+
+// checkSamples reports statements without samples, and samples without statement in the language
+func checkSamples(p problem, config Config) []string {
+	var samples []string
+	for _, test := range p.tests {
+		if test.UseInStatements {
+			samples = append(samples, strconv.Itoa(test.Index))
+		}
+	}
+	_, ok := p.statements[config.Language]
+	switch {
+	case len(samples) == 0 && len(p.statements) > 0:
+		return []string{"no test is used in statements"}
+	case len(samples) > 0 && !ok:
+		return []string{fmt.Sprintf("tests used in statements (%s), but there is no %s statement", strings.Join(samples, ", "), config.Language)}
+	}
+	return nil
+}
+
+// groupGraph builds the group graph of the problem, ignoring invalid points,
+// which are reported by checkPoints
+func groupGraph(p problem) *polygon.GroupGraph {
+	tests := make([]polygon.TestObject, len(p.tests))
+	for i, test := range p.tests {
+		if _, err := polygon.ParsePoints(test.Points); err != nil {
+			test.Points = ""
+		}
+		tests[i] = test
+	}
+	graph, _ := polygon.NewGroupGraph(p.groups, tests)
+	return graph
+}
+
+// groupIssues returns the messages of the issues of the group graph of the given kinds
+func groupIssues(p problem, kinds ...string) (messages []string) {
+	for _, issue := range groupGraph(p).Issues() {
+		for _, kind := range kinds {
+			if issue.Kind == kind {
+				messages = append(messages, issue.Message)
+			}
+		}
+	}
+	return messages
+}
+
+// checkGroups reports tests without group when groups are used, tests in undefined groups,
+// unknown points policies and points given to the sample group
+func checkGroups(p problem, config Config) []string {
+	return groupIssues(p, polygon.GroupIssueUngrouped, polygon.GroupIssueUnknownGroup, polygon.GroupIssuePolicy, polygon.GroupIssueSamplePoints)
+}
+
+// checkPoints reports invalid points, and points which do not sum up to 100
+func checkPoints(p problem, config Config) []string {
+	graph, err := polygon.NewGroupGraph(p.groups, p.tests)
+	if err != nil {
+		return []string{strings.TrimPrefix(err.Error(), "polygon: ")}
+	}
+	if total := graph.Points(); total != 0 && math.Abs(total-100) > 1e-6 {
+		return []string{fmt.Sprintf("points sum up to %s, expected 100", strconv.FormatFloat(total, 'f', -1, 64))}
+	}
+	return nil
+}
+
+// checkDependencies reports unknown groups and cycles in the dependencies of the groups
+func checkDependencies(p problem, config Config) []string {
+	return groupIssues(p, polygon.GroupIssueCycle, polygon.GroupIssueUnknownDependency)
+}
+
+// checkTutorial reports problems without tutorial
+func checkTutorial(p problem, config Config) []string {
+	if strings.TrimSpace(p.statements[config.Language].Tutorial) == "" && strings.TrimSpace(p.tutorial) == "" {
+		return []string{fmt.Sprintf("no tutorial, neither in the %s statement nor in the general tutorial", config.Language)}
+	}
+	return nil
+}
+
+// checkTags reports problems without tags
+func checkTags(p problem, config Config) []string {
+	if len(p.tags) == 0 {
+		return []string{"no tags"}
+	}
+	return nil
+}
+
+// Lint checks the problem against the rules which are not turned off, in the order of Rules.
+// The context stops the requests to the API when it is done.
+func Lint(ctx context.Context, client polygon.ProblemReader, config Config) (issues []Issue, err error) {
+	if config.Testset == "" {
+		config.Testset = "tests"
+	}
+	if config.Language == "" {
+		config.Language = "english"
+	}
+	for rule, severity := range config.Rules {
+		if _, ok := checks[rule]; !ok {
+			return issues, fmt.Errorf("lint: unknown rule %q", rule)
+		}
+		if severity != Error && severity != Warning && severity != Off {
+			return issues, fmt.Errorf("lint: unknown severity %q of rule %s", severity, rule)
+		}
+	}
+
+	p, err := load(ctx, client, config)
+	if err != nil {
+		return issues, err
+	}
+	for _, rule := range Rules() {
+		severity := config.severity(rule)
+		if severity == Off {
+			continue
+		}
+		for _, message := range checks[rule](p, config) {
+			issues = append(issues, Issue{Rule: rule, Severity: severity, Message: message})
+		}
+	}
+	return issues, nil
+}
+
+// Failed tells whether an issue has the Error severity
+func Failed(issues []Issue) bool {
+	for _, issue := range issues {
+		if issue.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteJSON writes the issues as a JSON array, an empty one if there are none
+func WriteJSON(w io.Writer, issues []Issue) error {
+	if issues == nil {
+		issues = []Issue{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", " ")
+	return encoder.Encode(issues)
+}
@@ -0,0 +1,58 @@
+package lint
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/polygonmock"
+)
+
+func TestGroupChecksWithInvalidPoints(t *testing.T) {
+	p := problem{
+		groups: []polygon.TestGroupObject{
+			{Name: "1", PointsPolicy: polygon.PointsPolicyEachTest, Dependencies: "2"},
+			{Name: "2", PointsPolicy: polygon.PointsPolicyEachTest, Dependencies: "1"},
+		},
+		tests: []polygon.TestObject{
+			{Index: 1, Groups: "1", Points: "ten"},
+			{Index: 2, Groups: "3", Points: "100"},
+		},
+	}
+	tests := []struct {
+		rule string
+		want []string
+	}{
+		{RulePoints, []string{"test 1 has invalid points \"ten\""}},
+		{RuleDependencies, []string{"dependency cycle: 1 -> 2 -> 1"}},
+		{RuleGroups, []string{"tests 2 are in group 3, which is not defined"}},
+	}
+	for _, check := range tests {
+		if got := checks[check.rule](p, Config{}); !reflect.DeepEqual(got, check.want) {
+			t.Errorf("%s: got %q, want %q", check.rule, got, check.want)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	client := &polygonmock.Client{
+		ProblemSolutionsFunc: func(map[string]string) ([]polygon.SolutionObject, error) {
+			return []polygon.SolutionObject{{Name: "main.cpp", Tag: "MA"}}, nil
+		},
+		ProblemCheckerFunc:  func(map[string]string) (string, error) { return "std::wcmp.cpp", nil },
+		ProblemViewTagsFunc: func(map[string]string) ([]string, error) { return []string{"math"}, nil },
+	}
+	issues, err := Lint(context.Background(), client, Config{Rules: map[string]Severity{RuleTutorial: Off}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Issue{{Rule: RuleValidator, Severity: Error, Message: "no validator is set"}}
+	if !reflect.DeepEqual(issues, want) || !Failed(issues) {
+		t.Errorf("got %v, want %v", issues, want)
+	}
+
+	if _, err = Lint(context.Background(), client, Config{Rules: map[string]Severity{"spelling": Off}}); err == nil {
+		t.Error("an unknown rule was accepted")
+	}
+}
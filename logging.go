@@ -0,0 +1,74 @@
+package polygon
+
+import (
+	"strconv"
+	"time"
+)
+
+// Logger is the subset of *slog.Logger used by LoggingInterceptor,
+// so a *slog.Logger (or any compatible logger) can be passed directly.
+type Logger interface {
+	Info(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// maxLoggedValueLength is the longest parameter value that is logged verbatim
+const maxLoggedValueLength = 64
+
+// sensitiveParameters are never logged
+var sensitiveParameters = map[string]bool{
+	"apiKey": true,
+	"apiSig": true,
+	"secret": true,
+}
+
+// RedactParameters returns a copy of the parameters which is safe to log.
+// Credentials are masked, and long values (file contents, test inputs, etc)
+// are replaced by their length.
+func RedactParameters(parameters map[string]string) map[string]string {
+	redacted := make(map[string]string, len(parameters))
+	for key, value := range parameters {
+		switch {
+		case sensitiveParameters[key]:
+			redacted[key] = "[REDACTED]"
+		case len(value) > maxLoggedValueLength:
+			redacted[key] = "[" + strconv.Itoa(len(value)) + " bytes]"
+		default:
+			redacted[key] = value
+		}
+	}
+	return redacted
+}
+
+// LoggingInterceptor returns an Interceptor that logs every call with its
+// method name, redacted parameters, status code and latency.
+// Successful calls are logged at Info level, failed calls at Error level.
+func LoggingInterceptor(logger Logger) Interceptor {
+	return func(request *Request, next Handler) (response *Response, err error) {
+		start := time.Now()
+		response, err = next(request)
+		latency := time.Since(start)
+
+		args := []interface{}{
+			"method", request.Method,
+			"problemId", request.ProblemId,
+			"params", RedactParameters(request.Parameters),
+			"status", statusCode(response),
+			"latency", latency,
+		}
+		if err != nil {
+			logger.Error("polygon request failed", append(args, "error", err)...)
+		} else {
+			logger.Info("polygon request", args...)
+		}
+		return response, err
+	}
+}
+
+// statusCode returns the HTTP status code of the response, or 0 if there is none
+func statusCode(response *Response) int {
+	if response == nil {
+		return 0
+	}
+	return response.StatusCode
+}
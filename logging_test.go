@@ -0,0 +1,66 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactParameters(t *testing.T) {
+	long := strings.Repeat("x", maxLoggedValueLength+1)
+	parameters := map[string]string{
+		"apiKey":    "key123",
+		"apiSig":    "sig456",
+		"secret":    "secret789",
+		"testIndex": "3",
+		"testInput": long,
+	}
+	redacted := RedactParameters(parameters)
+	want := map[string]string{
+		"apiKey":    "[REDACTED]",
+		"apiSig":    "[REDACTED]",
+		"secret":    "[REDACTED]",
+		"testIndex": "3",
+		"testInput": fmt.Sprintf("[%d bytes]", len(long)),
+	}
+	for key, value := range want {
+		if redacted[key] != value {
+			t.Errorf("%s: got %q, want %q", key, redacted[key], value)
+		}
+	}
+	if parameters["apiKey"] != "key123" {
+		t.Error("RedactParameters modified its argument")
+	}
+}
+
+// recordingLogger keeps the messages and arguments it logs
+type recordingLogger struct {
+	lines []string
+}
+
+func (logger *recordingLogger) Info(msg string, args ...interface{}) {
+	logger.lines = append(logger.lines, fmt.Sprint(append([]interface{}{"info ", msg}, args...)...))
+}
+
+func (logger *recordingLogger) Error(msg string, args ...interface{}) {
+	logger.lines = append(logger.lines, fmt.Sprint(append([]interface{}{"error ", msg}, args...)...))
+}
+
+func TestLoggingInterceptorHidesCredentials(t *testing.T) {
+	logger := &recordingLogger{}
+	logging := LoggingInterceptor(logger)
+	request := &Request{Method: problemInfoEp, Parameters: map[string]string{"apiKey": "key123", "apiSig": "sig456"}, Context: context.Background()}
+	logging(request, func(*Request) (*Response, error) { return &Response{StatusCode: 200}, nil })
+	logging(request, func(*Request) (*Response, error) { return nil, errors.New("refused") })
+
+	if len(logger.lines) != 2 || !strings.HasPrefix(logger.lines[0], "info ") || !strings.HasPrefix(logger.lines[1], "error ") {
+		t.Fatalf("logged %q, want an info and an error line", logger.lines)
+	}
+	for _, line := range logger.lines {
+		if strings.Contains(line, "key123") || strings.Contains(line, "sig456") {
+			t.Errorf("credentials logged: %s", line)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package polygon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CallStats describes a finished API call, as reported to Metrics
+//
+// Method        : the API method
+//
+// ErrorClass    : class of the error (see ErrorClass), empty if the call succeeded
+//
+// Latency       : time taken by the call
+//
+// BytesSent     : size of the request parameters
+//
+// BytesReceived : size of the response body
+type CallStats struct {
+	Method        string
+	ErrorClass    string
+	Latency       time.Duration
+	BytesSent     int
+	BytesReceived int
+}
+
+// Metrics receives measurements from MetricsInterceptor, RetryInterceptor and RateLimitInterceptor.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	ObserveCall(stats CallStats)
+	ObserveRetry(method string)
+	ObserveRateLimitWait(method string, wait time.Duration)
+}
+
+// MetricsInterceptor returns an Interceptor that reports every call to metrics.
+func MetricsInterceptor(metrics Metrics) Interceptor {
+	return func(request *Request, next Handler) (response *Response, err error) {
+		start := time.Now()
+		response, err = next(request)
+
+		stats := CallStats{
+			Method:     request.Method,
+			ErrorClass: ErrorClass(err),
+			Latency:    time.Since(start),
+		}
+		for key, value := range request.Parameters {
+			stats.BytesSent += len(key) + len(value)
+		}
+		if response != nil {
+			stats.BytesReceived = len(response.Body)
+		}
+		metrics.ObserveCall(stats)
+		return response, err
+	}
+}
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency histogram of MetricsRegistry
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// endpointMetrics holds all the measurements of a single API method
+type endpointMetrics struct {
+	calls         int64
+	errors        map[string]int64
+	buckets       []int64
+	latencySum    float64
+	bytesSent     int64
+	bytesReceived int64
+	retries       int64
+	waits         int64
+	waitSum       float64
+}
+
+// MetricsRegistry is an in-memory implementation of Metrics,
+// which can render its content in the Prometheus text exposition format.
+//
+// It implements http.Handler, so it can be mounted directly on a /metrics route.
+type MetricsRegistry struct {
+	mu        sync.Mutex
+	buckets   []float64
+	endpoints map[string]*endpointMetrics
+}
+
+// NewMetricsRegistry creates an empty registry using DefaultLatencyBuckets
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		buckets:   DefaultLatencyBuckets,
+		endpoints: make(map[string]*endpointMetrics),
+	}
+}
+
+// endpoint returns the metrics of a method, creating them if needed. The lock must be held.
+func (registry *MetricsRegistry) endpoint(method string) *endpointMetrics {
+	metrics, ok := registry.endpoints[method]
+	if !ok {
+		metrics = &endpointMetrics{
+			errors:  make(map[string]int64),
+			buckets: make([]int64, len(registry.buckets)),
+		}
+		registry.endpoints[method] = metrics
+	}
+	return metrics
+}
+
+// ObserveCall records a finished call
+func (registry *MetricsRegistry) ObserveCall(stats CallStats) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	metrics := registry.endpoint(stats.Method)
+	metrics.calls++
+	if stats.ErrorClass != "" {
+		metrics.errors[stats.ErrorClass]++
+	}
+
+	seconds := stats.Latency.Seconds()
+	metrics.latencySum += seconds
+	for i, bound := range registry.buckets {
+		if seconds <= bound {
+			metrics.buckets[i]++
+		}
+	}
+	metrics.bytesSent += int64(stats.BytesSent)
+	metrics.bytesReceived += int64(stats.BytesReceived)
+}
+
+// ObserveRetry records a retried call
+func (registry *MetricsRegistry) ObserveRetry(method string) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.endpoint(method).retries++
+}
+
+// ObserveRateLimitWait records a call delayed by rate limiting
+func (registry *MetricsRegistry) ObserveRateLimitWait(method string, wait time.Duration) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	metrics := registry.endpoint(method)
+	metrics.waits++
+	metrics.waitSum += wait.Seconds()
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format
+func (registry *MetricsRegistry) WritePrometheus(w io.Writer) error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	methods := make([]string, 0, len(registry.endpoints))
+	for method := range registry.endpoints {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	out := bufio.NewWriter(w)
+	writeFamily := func(name, kind, help string, write func(method string, metrics *endpointMetrics)) {
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		for _, method := range methods {
+			write(method, registry.endpoints[method])
+		}
+	}
+	counter := func(name, help string, value func(metrics *endpointMetrics) string) {
+		writeFamily(name, "counter", help, func(method string, metrics *endpointMetrics) {
+			fmt.Fprintf(out, "%s{method=%s} %s\n", name, quoteLabel(method), value(metrics))
+		})
+	}
+
+	counter("polygon_calls_total", "Number of API calls.", func(metrics *endpointMetrics) string {
+		return strconv.FormatInt(metrics.calls, 10)
+	})
+
+	writeFamily("polygon_errors_total", "counter", "Number of failed API calls by error class.",
+		func(method string, metrics *endpointMetrics) {
+			classes := make([]string, 0, len(metrics.errors))
+			for class := range metrics.errors {
+				classes = append(classes, class)
+			}
+			sort.Strings(classes)
+			for _, class := range classes {
+				fmt.Fprintf(out, "polygon_errors_total{method=%s,class=%s} %d\n",
+					quoteLabel(method), quoteLabel(class), metrics.errors[class])
+			}
+		})
+
+	writeFamily("polygon_request_duration_seconds", "histogram", "Latency of API calls.",
+		func(method string, metrics *endpointMetrics) {
+			for i, bound := range registry.buckets {
+				fmt.Fprintf(out, "polygon_request_duration_seconds_bucket{method=%s,le=%s} %d\n",
+					quoteLabel(method), quoteLabel(formatFloat(bound)), metrics.buckets[i])
+			}
+			fmt.Fprintf(out, "polygon_request_duration_seconds_bucket{method=%s,le=\"+Inf\"} %d\n",
+				quoteLabel(method), metrics.calls)
+			fmt.Fprintf(out, "polygon_request_duration_seconds_sum{method=%s} %s\n",
+				quoteLabel(method), formatFloat(metrics.latencySum))
+			fmt.Fprintf(out, "polygon_request_duration_seconds_count{method=%s} %d\n",
+				quoteLabel(method), metrics.calls)
+		})
+
+	counter("polygon_bytes_sent_total", "Bytes of request parameters sent.", func(metrics *endpointMetrics) string {
+		return strconv.FormatInt(metrics.bytesSent, 10)
+	})
+	counter("polygon_bytes_received_total", "Bytes of response bodies received.", func(metrics *endpointMetrics) string {
+		return strconv.FormatInt(metrics.bytesReceived, 10)
+	})
+	counter("polygon_retries_total", "Number of retried API calls.", func(metrics *endpointMetrics) string {
+		return strconv.FormatInt(metrics.retries, 10)
+	})
+	counter("polygon_rate_limit_waits_total", "Number of API calls delayed by rate limiting.", func(metrics *endpointMetrics) string {
+		return strconv.FormatInt(metrics.waits, 10)
+	})
+	counter("polygon_rate_limit_wait_seconds_total", "Time spent waiting for rate limiting.", func(metrics *endpointMetrics) string {
+		return formatFloat(metrics.waitSum)
+	})
+
+	return out.Flush()
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format
+func (registry *MetricsRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	registry.WritePrometheus(w)
+}
+
+// quoteLabel quotes a label value according to the exposition format
+func quoteLabel(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// formatFloat formats a sample value
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
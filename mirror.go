@@ -0,0 +1,209 @@
+package polygon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrOffline is returned by write endpoints when the client is served from an offline mirror
+var ErrOffline = errors.New("polygon: write endpoints are not available in offline mode")
+
+// mirrorIntegerParameters are the parameters holding numbers, which may be written with leading zeros
+var mirrorIntegerParameters = []string{"testIndex", "packageId", "contestId"}
+
+// mirrorParameters normalises the parameters of a request, so that equivalent requests share
+// their entry: noInputs is dropped, the full response being served in its place, and numbers
+// are formatted without leading zeros
+func mirrorParameters(parameters map[string]string) map[string]string {
+	normalised := make(map[string]string, len(parameters))
+	for key, value := range parameters {
+		normalised[key] = value
+	}
+	delete(normalised, "noInputs")
+	for _, key := range mirrorIntegerParameters {
+		if number, err := strconv.Atoi(strings.TrimSpace(normalised[key])); err == nil {
+			normalised[key] = strconv.Itoa(number)
+		}
+	}
+	return normalised
+}
+
+// mirrorPath returns the file of the mirror holding the response to a request.
+//
+// The layout is <dir>/<problemId>/<method>/<hash of the normalised parameters>, using "_" as problemId
+// for methods which are not bound to a problem.
+func mirrorPath(dir string, request *Request) string {
+	problemDir := "_"
+	if request.ProblemId != "" {
+		problemDir = url.PathEscape(request.ProblemId)
+	}
+
+	hash := sha256.Sum256([]byte(canonicalParameters(mirrorParameters(request.Parameters))))
+	return filepath.Join(dir, problemDir, request.Method, hex.EncodeToString(hash[:16]))
+}
+
+// readMirror serves a request from the mirror at api.MirrorDir
+func (api *PolygonApi) readMirror(request *Request) (response *Response, err error) {
+	if writeEndpoints[request.Method] {
+		return response, ErrOffline
+	}
+
+	body, err := ioutil.ReadFile(mirrorPath(api.MirrorDir, request))
+	if os.IsNotExist(err) {
+		response = &Response{StatusCode: http.StatusNotFound}
+		return response, &ApiError{
+			Method:     request.Method,
+			StatusCode: http.StatusNotFound,
+			Comment:    "mirror: response not found in the offline mirror",
+		}
+	}
+	if err != nil {
+		return response, err
+	}
+	return &Response{StatusCode: http.StatusOK, Body: body}, nil
+}
+
+// MirrorRecorder returns an Interceptor which stores every successful read-only response
+// into the mirror at dir, so that it can later be served through PolygonApi.MirrorDir.
+func MirrorRecorder(dir string) Interceptor {
+	return func(request *Request, next Handler) (response *Response, err error) {
+		response, err = next(request)
+		if err != nil || writeEndpoints[request.Method] {
+			return response, err
+		}
+
+		path := mirrorPath(dir, request)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return response, err
+		}
+		return response, writeFileAtomic(path, response.Body)
+	}
+}
+
+// ExportMirror downloads everything the read endpoints expose about the current problem
+// into the mirror at dir: the list of problems, info, statements and their resources, checker, validator, interactor,
+// every file and solution, tags, general description and tutorial, packages, and for each of
+// the given testsets its script, tests (with inputs and answers) and test groups.
+func (api *PolygonApi) ExportMirror(dir string, testsets []string) (err error) {
+	recorder := *api
+	recorder.MirrorDir = ""
+	recorder.Interceptors = append([]Interceptor{MirrorRecorder(dir)}, api.Interceptors...)
+	none := map[string]string{}
+
+	if _, err = recorder.ProblemsList(none); err != nil {
+		return err
+	}
+	if _, err = recorder.ProblemInfo(none); err != nil {
+		return err
+	}
+	if _, err = recorder.ProblemStatements(none); err != nil {
+		return err
+	}
+	if _, err = recorder.ProblemStatementResources(none); err != nil {
+		return err
+	}
+	if _, err = recorder.ProblemChecker(none); err != nil {
+		return err
+	}
+	if _, err = recorder.ProblemValidator(none); err != nil {
+		return err
+	}
+	if _, err = recorder.ProblemInteractor(none); err != nil {
+		return err
+	}
+
+	rsa, err := recorder.ProblemFiles(none)
+	if err != nil {
+		return err
+	}
+	fileGroups := map[string][]FileObject{
+		"resource": rsa.ResourceFiles,
+		"source":   rsa.SourceFiles,
+		"aux":      rsa.AuxFiles,
+	}
+	for fileType, files := range fileGroups {
+		for _, file := range files {
+			parameters := map[string]string{"type": fileType, "name": file.Name}
+			if _, err = recorder.ProblemViewFile(parameters); err != nil {
+				return err
+			}
+		}
+	}
+
+	solutions, err := recorder.ProblemSolutions(none)
+	if err != nil {
+		return err
+	}
+	for _, solution := range solutions {
+		if _, err = recorder.ProblemViewSolution(map[string]string{"name": solution.Name}); err != nil {
+			return err
+		}
+	}
+
+	for _, testset := range testsets {
+		parameters := map[string]string{"testset": testset}
+		if _, err = recorder.ProblemScript(parameters); err != nil {
+			return err
+		}
+		// Fails when groups are not enabled for the testset
+		recorder.ProblemViewTestGroup(parameters)
+
+		tests, err := recorder.ProblemTests(parameters)
+		if err != nil {
+			return err
+		}
+		for _, test := range tests {
+			testParameters := map[string]string{"testset": testset, "testIndex": strconv.Itoa(test.Index)}
+			if _, err = recorder.ProblemTestInput(testParameters); err != nil {
+				return err
+			}
+			if _, err = recorder.ProblemTestAnswer(testParameters); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err = recorder.ProblemViewTags(none); err != nil {
+		return err
+	}
+	if _, err = recorder.ProblemViewGeneralDescription(none); err != nil {
+		return err
+	}
+	if _, err = recorder.ProblemViewGeneralTutorial(none); err != nil {
+		return err
+	}
+	_, err = recorder.ProblemPackages(none)
+	return err
+}
+
+// ExportContestMirror downloads the problems of a contest into the mirror at dir,
+// then exports the mirror of each problem as ExportMirror does
+func (api *PolygonApi) ExportContestMirror(dir, contestId string, testsets []string) error {
+	recorder := *api
+	recorder.MirrorDir = ""
+	recorder.Interceptors = append([]Interceptor{MirrorRecorder(dir)}, api.Interceptors...)
+
+	problems, err := recorder.ContestProblems(map[string]string{"contestId": contestId})
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		if problem.Deleted {
+			continue
+		}
+		problemApi := *api
+		problemApi.ProblemId = strconv.Itoa(problem.Id)
+		if err = problemApi.ExportMirror(dir, testsets); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package polygon
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestMirrorParameters(t *testing.T) {
+	tests := []struct {
+		parameters map[string]string
+		want       string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"testset": "tests", "noInputs": "true"}, "testset=tests"},
+		{map[string]string{"testset": "tests", "testIndex": "007"}, "testIndex=7&testset=tests"},
+		{map[string]string{"name": "007.cpp"}, "name=007.cpp"},
+		{map[string]string{"testIndex": "x"}, "testIndex=x"},
+	}
+	for _, test := range tests {
+		if got := canonicalParameters(mirrorParameters(test.parameters)); got != test.want {
+			t.Errorf("mirrorParameters(%v) = %q, want %q", test.parameters, got, test.want)
+		}
+	}
+}
+
+func TestMirrorServesEquivalentRequests(t *testing.T) {
+	dir := t.TempDir()
+	recorder := MirrorRecorder(dir)
+	record := func(method, problemId string, parameters map[string]string, body string) {
+		request := &Request{Method: method, ProblemId: problemId, Parameters: parameters}
+		_, err := recorder(request, func(*Request) (*Response, error) {
+			return &Response{StatusCode: http.StatusOK, Body: []byte(body)}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	record(problemsListEp, "", map[string]string{}, `{"status":"OK","result":[{"id":1}]}`)
+	record(problemTestsEp, "1", map[string]string{"testset": "tests"}, `{"status":"OK","result":[{"index":1,"manual":true,"input":"1 2"}]}`)
+	record(problemTestInputEp, "1", map[string]string{"testset": "tests", "testIndex": "1"}, "1 2\n")
+
+	api := &PolygonApi{ProblemId: "1", MirrorDir: dir}
+	problems, err := api.ProblemsList(map[string]string{})
+	if err != nil || len(problems) != 1 {
+		t.Errorf("ProblemsList = %v, %v", problems, err)
+	}
+	tests, err := api.ProblemTests(map[string]string{"testset": "tests", "noInputs": "true"})
+	if err != nil || len(tests) != 1 || tests[0].Index != 1 {
+		t.Errorf("ProblemTests with noInputs = %v, %v", tests, err)
+	}
+	input, err := api.ProblemTestInput(map[string]string{"testset": "tests", "testIndex": "01"})
+	if err != nil || input != "1 2\n" {
+		t.Errorf("ProblemTestInput(01) = %q, %v", input, err)
+	}
+	if _, err = api.ProblemTestInput(map[string]string{"testset": "tests", "testIndex": "2"}); ErrorClass(err) != ErrorClassNotFound {
+		t.Errorf("missing entry: got %v, want a not found error", err)
+	}
+	if err = api.ProblemSaveTags(map[string]string{"tags": "dp"}); !errors.Is(err, ErrOffline) {
+		t.Errorf("write endpoint: got %v, want ErrOffline", err)
+	}
+}
@@ -1,14 +1,32 @@
 package polygon
 
 import (
+	"context"
 	"encoding/json"
 )
 
 // PolygonApi stores metadata for API calls
+//
+// Interceptors are optional, they are run around every API call (see Interceptor).
+//
+// MirrorDir is optional, when set the client works offline: read endpoints are served
+// from the mirror previously exported to that directory (see ExportMirror),
+// and write endpoints fail with ErrOffline.
 type PolygonApi struct {
-	ApiKey    string
-	Secret    string
-	ProblemId string
+	ApiKey       string
+	Secret       string
+	ProblemId    string
+	Interceptors []Interceptor
+	MirrorDir    string
+	ctx          context.Context
+}
+
+// WithContext returns a copy of the client whose calls carry ctx: the HTTP requests are
+// cancelled with it, and interceptors receive it, e.g. to join the trace of the caller.
+func (api *PolygonApi) WithContext(ctx context.Context) *PolygonApi {
+	copied := *api
+	copied.ctx = ctx
+	return &copied
 }
 
 // A handy struct to unmarshal response which returns a string as result
@@ -567,6 +567,17 @@ func (api *PolygonApi) ProblemPackages(parameters map[string]string) (packages [
 	return wrapper.Result, err
 }
 
+// ProblemPackage returns the content of a package as a zip archive.
+//
+// Parameters
+//
+// packageId : package’s id
+//
+// type : optional - standard, linux or windows, the type of the package (defaults to standard)
+func (api *PolygonApi) ProblemPackage(parameters map[string]string) (packageData []byte, err error) {
+	return api.processRequest(parameters, problemPackageEp)
+}
+
 // ContestProblems returns a list of Problem objects - problems of the contest.
 //
 // Parameters
@@ -582,5 +593,3 @@ func (api *PolygonApi) ContestProblems(parameters map[string]string) (problems [
 	err = json.Unmarshal(body, &wrapper)
 	return wrapper.Result, err
 }
-
-// Skipped : ProblemPackage
@@ -0,0 +1,444 @@
+// Code generated by internal/mockgen; DO NOT EDIT.
+
+// Package polygonmock provides a mock implementation of polygon.Client for unit tests.
+package polygonmock
+
+import (
+	"sync"
+
+	"github.com/variety-jones/polygon"
+)
+
+// Call records a single call made to the mock
+type Call struct {
+	Method     string
+	Parameters map[string]string
+}
+
+// Client is a mock implementation of polygon.Client.
+//
+// Each method records the call, then delegates to the field of the same name suffixed
+// with Func. When that field is nil, the method returns zero values and a nil error.
+type Client struct {
+	mu    sync.Mutex
+	calls []Call
+
+	ProblemsListFunc                  func(map[string]string) ([]polygon.ProblemObject, error)
+	ProblemInfoFunc                   func(map[string]string) (polygon.ProblemInfoObject, error)
+	ProblemStatementsFunc             func(map[string]string) (map[string]polygon.StatementObject, error)
+	ProblemStatementResourcesFunc     func(map[string]string) ([]polygon.FileObject, error)
+	ProblemCheckerFunc                func(map[string]string) (string, error)
+	ProblemValidatorFunc              func(map[string]string) (string, error)
+	ProblemInteractorFunc             func(map[string]string) (string, error)
+	ProblemFilesFunc                  func(map[string]string) (polygon.RsaObject, error)
+	ProblemSolutionsFunc              func(map[string]string) ([]polygon.SolutionObject, error)
+	ProblemViewFileFunc               func(map[string]string) (string, error)
+	ProblemViewSolutionFunc           func(map[string]string) (string, error)
+	ProblemScriptFunc                 func(map[string]string) (string, error)
+	ProblemTestsFunc                  func(map[string]string) ([]polygon.TestObject, error)
+	ProblemTestInputFunc              func(map[string]string) (string, error)
+	ProblemTestAnswerFunc             func(map[string]string) (string, error)
+	ProblemViewTestGroupFunc          func(map[string]string) ([]polygon.TestGroupObject, error)
+	ProblemViewTagsFunc               func(map[string]string) ([]string, error)
+	ProblemViewGeneralDescriptionFunc func(map[string]string) (string, error)
+	ProblemViewGeneralTutorialFunc    func(map[string]string) (string, error)
+	ProblemPackagesFunc               func(map[string]string) ([]polygon.PackageObject, error)
+	ProblemPackageFunc                func(map[string]string) ([]byte, error)
+	ProblemUpdateInfoFunc             func(map[string]string) error
+	ProblemSaveStatementFunc          func(map[string]string) error
+	ProblemSaveStatementResourceFunc  func(map[string]string) error
+	ProblemSetValidatorFunc           func(map[string]string) error
+	ProblemSetCheckerFunc             func(map[string]string) error
+	ProblemSetInteractorFunc          func(map[string]string) error
+	ProblemSaveFileFunc               func(map[string]string) error
+	ProblemSaveSolutionFunc           func(map[string]string) error
+	ProblemEditSolutionExtraTagsFunc  func(map[string]string) error
+	ProblemSaveScriptFunc             func(map[string]string) error
+	ProblemSaveTestFunc               func(map[string]string) error
+	ProblemSetTestGroupFunc           func(map[string]string) error
+	ProblemEnableGroupsFunc           func(map[string]string) error
+	ProblemEnablePointsFunc           func(map[string]string) error
+	ProblemSaveTestGroupsFunc         func(map[string]string) error
+	ProblemSaveTagsFunc               func(map[string]string) error
+	ProblemSaveGeneralDescriptionFunc func(map[string]string) error
+	ProblemSaveGeneralTutorialFunc    func(map[string]string) error
+	ContestProblemsFunc               func(map[string]string) ([]polygon.ProblemObject, error)
+}
+
+var _ polygon.Client = (*Client)(nil)
+
+// Calls returns the calls made to the mock so far, in order
+func (m *Client) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// record appends a call to the list of calls
+func (m *Client) record(method string, parameters map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Parameters: parameters})
+}
+
+// ProblemsList calls ProblemsListFunc
+func (m *Client) ProblemsList(parameters map[string]string) (r0 []polygon.ProblemObject, r1 error) {
+	m.record("ProblemsList", parameters)
+	if m.ProblemsListFunc == nil {
+		return
+	}
+	return m.ProblemsListFunc(parameters)
+}
+
+// ProblemInfo calls ProblemInfoFunc
+func (m *Client) ProblemInfo(parameters map[string]string) (r0 polygon.ProblemInfoObject, r1 error) {
+	m.record("ProblemInfo", parameters)
+	if m.ProblemInfoFunc == nil {
+		return
+	}
+	return m.ProblemInfoFunc(parameters)
+}
+
+// ProblemStatements calls ProblemStatementsFunc
+func (m *Client) ProblemStatements(parameters map[string]string) (r0 map[string]polygon.StatementObject, r1 error) {
+	m.record("ProblemStatements", parameters)
+	if m.ProblemStatementsFunc == nil {
+		return
+	}
+	return m.ProblemStatementsFunc(parameters)
+}
+
+// ProblemStatementResources calls ProblemStatementResourcesFunc
+func (m *Client) ProblemStatementResources(parameters map[string]string) (r0 []polygon.FileObject, r1 error) {
+	m.record("ProblemStatementResources", parameters)
+	if m.ProblemStatementResourcesFunc == nil {
+		return
+	}
+	return m.ProblemStatementResourcesFunc(parameters)
+}
+
+// ProblemChecker calls ProblemCheckerFunc
+func (m *Client) ProblemChecker(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemChecker", parameters)
+	if m.ProblemCheckerFunc == nil {
+		return
+	}
+	return m.ProblemCheckerFunc(parameters)
+}
+
+// ProblemValidator calls ProblemValidatorFunc
+func (m *Client) ProblemValidator(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemValidator", parameters)
+	if m.ProblemValidatorFunc == nil {
+		return
+	}
+	return m.ProblemValidatorFunc(parameters)
+}
+
+// ProblemInteractor calls ProblemInteractorFunc
+func (m *Client) ProblemInteractor(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemInteractor", parameters)
+	if m.ProblemInteractorFunc == nil {
+		return
+	}
+	return m.ProblemInteractorFunc(parameters)
+}
+
+// ProblemFiles calls ProblemFilesFunc
+func (m *Client) ProblemFiles(parameters map[string]string) (r0 polygon.RsaObject, r1 error) {
+	m.record("ProblemFiles", parameters)
+	if m.ProblemFilesFunc == nil {
+		return
+	}
+	return m.ProblemFilesFunc(parameters)
+}
+
+// ProblemSolutions calls ProblemSolutionsFunc
+func (m *Client) ProblemSolutions(parameters map[string]string) (r0 []polygon.SolutionObject, r1 error) {
+	m.record("ProblemSolutions", parameters)
+	if m.ProblemSolutionsFunc == nil {
+		return
+	}
+	return m.ProblemSolutionsFunc(parameters)
+}
+
+// ProblemViewFile calls ProblemViewFileFunc
+func (m *Client) ProblemViewFile(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemViewFile", parameters)
+	if m.ProblemViewFileFunc == nil {
+		return
+	}
+	return m.ProblemViewFileFunc(parameters)
+}
+
+// ProblemViewSolution calls ProblemViewSolutionFunc
+func (m *Client) ProblemViewSolution(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemViewSolution", parameters)
+	if m.ProblemViewSolutionFunc == nil {
+		return
+	}
+	return m.ProblemViewSolutionFunc(parameters)
+}
+
+// ProblemScript calls ProblemScriptFunc
+func (m *Client) ProblemScript(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemScript", parameters)
+	if m.ProblemScriptFunc == nil {
+		return
+	}
+	return m.ProblemScriptFunc(parameters)
+}
+
+// ProblemTests calls ProblemTestsFunc
+func (m *Client) ProblemTests(parameters map[string]string) (r0 []polygon.TestObject, r1 error) {
+	m.record("ProblemTests", parameters)
+	if m.ProblemTestsFunc == nil {
+		return
+	}
+	return m.ProblemTestsFunc(parameters)
+}
+
+// ProblemTestInput calls ProblemTestInputFunc
+func (m *Client) ProblemTestInput(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemTestInput", parameters)
+	if m.ProblemTestInputFunc == nil {
+		return
+	}
+	return m.ProblemTestInputFunc(parameters)
+}
+
+// ProblemTestAnswer calls ProblemTestAnswerFunc
+func (m *Client) ProblemTestAnswer(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemTestAnswer", parameters)
+	if m.ProblemTestAnswerFunc == nil {
+		return
+	}
+	return m.ProblemTestAnswerFunc(parameters)
+}
+
+// ProblemViewTestGroup calls ProblemViewTestGroupFunc
+func (m *Client) ProblemViewTestGroup(parameters map[string]string) (r0 []polygon.TestGroupObject, r1 error) {
+	m.record("ProblemViewTestGroup", parameters)
+	if m.ProblemViewTestGroupFunc == nil {
+		return
+	}
+	return m.ProblemViewTestGroupFunc(parameters)
+}
+
+// ProblemViewTags calls ProblemViewTagsFunc
+func (m *Client) ProblemViewTags(parameters map[string]string) (r0 []string, r1 error) {
+	m.record("ProblemViewTags", parameters)
+	if m.ProblemViewTagsFunc == nil {
+		return
+	}
+	return m.ProblemViewTagsFunc(parameters)
+}
+
+// ProblemViewGeneralDescription calls ProblemViewGeneralDescriptionFunc
+func (m *Client) ProblemViewGeneralDescription(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemViewGeneralDescription", parameters)
+	if m.ProblemViewGeneralDescriptionFunc == nil {
+		return
+	}
+	return m.ProblemViewGeneralDescriptionFunc(parameters)
+}
+
+// ProblemViewGeneralTutorial calls ProblemViewGeneralTutorialFunc
+func (m *Client) ProblemViewGeneralTutorial(parameters map[string]string) (r0 string, r1 error) {
+	m.record("ProblemViewGeneralTutorial", parameters)
+	if m.ProblemViewGeneralTutorialFunc == nil {
+		return
+	}
+	return m.ProblemViewGeneralTutorialFunc(parameters)
+}
+
+// ProblemPackages calls ProblemPackagesFunc
+func (m *Client) ProblemPackages(parameters map[string]string) (r0 []polygon.PackageObject, r1 error) {
+	m.record("ProblemPackages", parameters)
+	if m.ProblemPackagesFunc == nil {
+		return
+	}
+	return m.ProblemPackagesFunc(parameters)
+}
+
+// ProblemPackage calls ProblemPackageFunc
+func (m *Client) ProblemPackage(parameters map[string]string) (r0 []byte, r1 error) {
+	m.record("ProblemPackage", parameters)
+	if m.ProblemPackageFunc == nil {
+		return
+	}
+	return m.ProblemPackageFunc(parameters)
+}
+
+// ProblemUpdateInfo calls ProblemUpdateInfoFunc
+func (m *Client) ProblemUpdateInfo(parameters map[string]string) (r0 error) {
+	m.record("ProblemUpdateInfo", parameters)
+	if m.ProblemUpdateInfoFunc == nil {
+		return
+	}
+	return m.ProblemUpdateInfoFunc(parameters)
+}
+
+// ProblemSaveStatement calls ProblemSaveStatementFunc
+func (m *Client) ProblemSaveStatement(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveStatement", parameters)
+	if m.ProblemSaveStatementFunc == nil {
+		return
+	}
+	return m.ProblemSaveStatementFunc(parameters)
+}
+
+// ProblemSaveStatementResource calls ProblemSaveStatementResourceFunc
+func (m *Client) ProblemSaveStatementResource(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveStatementResource", parameters)
+	if m.ProblemSaveStatementResourceFunc == nil {
+		return
+	}
+	return m.ProblemSaveStatementResourceFunc(parameters)
+}
+
+// ProblemSetValidator calls ProblemSetValidatorFunc
+func (m *Client) ProblemSetValidator(parameters map[string]string) (r0 error) {
+	m.record("ProblemSetValidator", parameters)
+	if m.ProblemSetValidatorFunc == nil {
+		return
+	}
+	return m.ProblemSetValidatorFunc(parameters)
+}
+
+// ProblemSetChecker calls ProblemSetCheckerFunc
+func (m *Client) ProblemSetChecker(parameters map[string]string) (r0 error) {
+	m.record("ProblemSetChecker", parameters)
+	if m.ProblemSetCheckerFunc == nil {
+		return
+	}
+	return m.ProblemSetCheckerFunc(parameters)
+}
+
+// ProblemSetInteractor calls ProblemSetInteractorFunc
+func (m *Client) ProblemSetInteractor(parameters map[string]string) (r0 error) {
+	m.record("ProblemSetInteractor", parameters)
+	if m.ProblemSetInteractorFunc == nil {
+		return
+	}
+	return m.ProblemSetInteractorFunc(parameters)
+}
+
+// ProblemSaveFile calls ProblemSaveFileFunc
+func (m *Client) ProblemSaveFile(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveFile", parameters)
+	if m.ProblemSaveFileFunc == nil {
+		return
+	}
+	return m.ProblemSaveFileFunc(parameters)
+}
+
+// ProblemSaveSolution calls ProblemSaveSolutionFunc
+func (m *Client) ProblemSaveSolution(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveSolution", parameters)
+	if m.ProblemSaveSolutionFunc == nil {
+		return
+	}
+	return m.ProblemSaveSolutionFunc(parameters)
+}
+
+// ProblemEditSolutionExtraTags calls ProblemEditSolutionExtraTagsFunc
+func (m *Client) ProblemEditSolutionExtraTags(parameters map[string]string) (r0 error) {
+	m.record("ProblemEditSolutionExtraTags", parameters)
+	if m.ProblemEditSolutionExtraTagsFunc == nil {
+		return
+	}
+	return m.ProblemEditSolutionExtraTagsFunc(parameters)
+}
+
+// ProblemSaveScript calls ProblemSaveScriptFunc
+func (m *Client) ProblemSaveScript(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveScript", parameters)
+	if m.ProblemSaveScriptFunc == nil {
+		return
+	}
+	return m.ProblemSaveScriptFunc(parameters)
+}
+
+// ProblemSaveTest calls ProblemSaveTestFunc
+func (m *Client) ProblemSaveTest(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveTest", parameters)
+	if m.ProblemSaveTestFunc == nil {
+		return
+	}
+	return m.ProblemSaveTestFunc(parameters)
+}
+
+// ProblemSetTestGroup calls ProblemSetTestGroupFunc
+func (m *Client) ProblemSetTestGroup(parameters map[string]string) (r0 error) {
+	m.record("ProblemSetTestGroup", parameters)
+	if m.ProblemSetTestGroupFunc == nil {
+		return
+	}
+	return m.ProblemSetTestGroupFunc(parameters)
+}
+
+// ProblemEnableGroups calls ProblemEnableGroupsFunc
+func (m *Client) ProblemEnableGroups(parameters map[string]string) (r0 error) {
+	m.record("ProblemEnableGroups", parameters)
+	if m.ProblemEnableGroupsFunc == nil {
+		return
+	}
+	return m.ProblemEnableGroupsFunc(parameters)
+}
+
+// ProblemEnablePoints calls ProblemEnablePointsFunc
+func (m *Client) ProblemEnablePoints(parameters map[string]string) (r0 error) {
+	m.record("ProblemEnablePoints", parameters)
+	if m.ProblemEnablePointsFunc == nil {
+		return
+	}
+	return m.ProblemEnablePointsFunc(parameters)
+}
+
+// ProblemSaveTestGroups calls ProblemSaveTestGroupsFunc
+func (m *Client) ProblemSaveTestGroups(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveTestGroups", parameters)
+	if m.ProblemSaveTestGroupsFunc == nil {
+		return
+	}
+	return m.ProblemSaveTestGroupsFunc(parameters)
+}
+
+// ProblemSaveTags calls ProblemSaveTagsFunc
+func (m *Client) ProblemSaveTags(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveTags", parameters)
+	if m.ProblemSaveTagsFunc == nil {
+		return
+	}
+	return m.ProblemSaveTagsFunc(parameters)
+}
+
+// ProblemSaveGeneralDescription calls ProblemSaveGeneralDescriptionFunc
+func (m *Client) ProblemSaveGeneralDescription(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveGeneralDescription", parameters)
+	if m.ProblemSaveGeneralDescriptionFunc == nil {
+		return
+	}
+	return m.ProblemSaveGeneralDescriptionFunc(parameters)
+}
+
+// ProblemSaveGeneralTutorial calls ProblemSaveGeneralTutorialFunc
+func (m *Client) ProblemSaveGeneralTutorial(parameters map[string]string) (r0 error) {
+	m.record("ProblemSaveGeneralTutorial", parameters)
+	if m.ProblemSaveGeneralTutorialFunc == nil {
+		return
+	}
+	return m.ProblemSaveGeneralTutorialFunc(parameters)
+}
+
+// ContestProblems calls ContestProblemsFunc
+func (m *Client) ContestProblems(parameters map[string]string) (r0 []polygon.ProblemObject, r1 error) {
+	m.record("ContestProblems", parameters)
+	if m.ContestProblemsFunc == nil {
+		return
+	}
+	return m.ContestProblemsFunc(parameters)
+}
@@ -0,0 +1,357 @@
+// Package problemxml reads and writes problem.xml, the descriptor found at the root
+// of the packages built by polygon (see PolygonApi.ProblemPackage).
+package problemxml
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Problem is the root element of problem.xml
+type Problem struct {
+	XMLName    xml.Name    `xml:"problem"`
+	Revision   int         `xml:"revision,attr"`
+	ShortName  string      `xml:"short-name,attr"`
+	URL        string      `xml:"url,attr,omitempty"`
+	Names      []Name      `xml:"names>name"`
+	Statements []Statement `xml:"statements>statement"`
+	Tutorials  []Statement `xml:"tutorials>tutorial"`
+	Judging    Judging     `xml:"judging"`
+	Files      Files       `xml:"files"`
+	Assets     Assets      `xml:"assets"`
+	Properties []Property  `xml:"properties>property"`
+	Stresses   *Stresses   `xml:"stresses,omitempty"`
+	Materials  []Material  `xml:"materials>material"`
+	Tags       []Tag       `xml:"tags>tag"`
+	Unknown
+}
+
+// Name is the name of the problem in a language
+type Name struct {
+	Language string `xml:"language,attr"`
+	Value    string `xml:"value,attr"`
+}
+
+// Statement is a statement or a tutorial in a given language and format
+//
+// Type : MIME type of the file, such as application/x-tex, text/html or application/pdf
+type Statement struct {
+	Charset  string `xml:"charset,attr,omitempty"`
+	Language string `xml:"language,attr"`
+	MathJax  bool   `xml:"mathjax,attr,omitempty"`
+	Path     string `xml:"path,attr"`
+	Type     string `xml:"type,attr"`
+	Unknown
+}
+
+// Judging describes how solutions are run
+//
+// InputFile, OutputFile : file names used instead of the standard streams, empty for stdin/stdout
+type Judging struct {
+	CPUName                         string    `xml:"cpu-name,attr,omitempty"`
+	CPUSpeed                        int       `xml:"cpu-speed,attr,omitempty"`
+	InputFile                       string    `xml:"input-file,attr"`
+	OutputFile                      string    `xml:"output-file,attr"`
+	RunCount                        int       `xml:"run-count,attr,omitempty"`
+	TreatPointsFromCheckerAsPercent bool      `xml:"treat-points-from-checker-as-percent,attr,omitempty"`
+	Testsets                        []Testset `xml:"testset"`
+	Unknown
+}
+
+// Testset is a testset of the problem, or the tests of a checker or a validator
+//
+// TimeLimit   : in milliseconds
+//
+// MemoryLimit : in bytes
+//
+// *PathPattern : printf patterns of the test files in the package, taking the index of the test
+type Testset struct {
+	Name              string  `xml:"name,attr,omitempty"`
+	TimeLimit         int     `xml:"time-limit,omitempty"`
+	MemoryLimit       int64   `xml:"memory-limit,omitempty"`
+	TestCount         int     `xml:"test-count"`
+	InputPathPattern  string  `xml:"input-path-pattern"`
+	OutputPathPattern string  `xml:"output-path-pattern,omitempty"`
+	AnswerPathPattern string  `xml:"answer-path-pattern,omitempty"`
+	Tests             []Test  `xml:"tests>test"`
+	Groups            []Group `xml:"groups>group"`
+	Unknown
+}
+
+// Test is a test of a testset, its index is its position in the testset starting at 1
+//
+// Method  : "manual" or "generated"
+//
+// Cmd     : generator command line of a generated test
+//
+// Verdict : expected verdict, for the tests of a checker
+//
+// Points  : nil when the problem has no points, a test may be worth 0
+type Test struct {
+	Method      string   `xml:"method,attr,omitempty"`
+	Cmd         string   `xml:"cmd,attr,omitempty"`
+	FromFile    string   `xml:"from-file,attr,omitempty"`
+	Sample      bool     `xml:"sample,attr,omitempty"`
+	Description string   `xml:"description,attr,omitempty"`
+	Points      *float64 `xml:"points,attr,omitempty"`
+	Group       string   `xml:"group,attr,omitempty"`
+	Verdict     string   `xml:"verdict,attr,omitempty"`
+	Unknown
+}
+
+// Group is a test group of a testset
+//
+// FeedbackPolicy : "complete", "icpc", "points" or "none"
+//
+// PointsPolicy   : "each-test" or "complete-group"
+//
+// Dependencies   : names of the groups which must be passed to get the points of this one
+//
+// Points         : nil when the points of the group are not set
+type Group struct {
+	Name           string       `xml:"name,attr"`
+	FeedbackPolicy string       `xml:"feedback-policy,attr,omitempty"`
+	Points         *float64     `xml:"points,attr,omitempty"`
+	PointsPolicy   string       `xml:"points-policy,attr,omitempty"`
+	Dependencies   []Dependency `xml:"dependencies>dependency"`
+	Unknown
+}
+
+// Dependency is a group required by another group
+type Dependency struct {
+	Group string `xml:"group,attr"`
+}
+
+// Files lists the resources (such as testlib.h and olymp.sty) and the executables (generators, ...)
+type Files struct {
+	Resources   []File       `xml:"resources>file"`
+	Executables []Executable `xml:"executables>executable"`
+	Unknown
+}
+
+// File is a file of the package, with its polygon source type if any
+type File struct {
+	Path string `xml:"path,attr"`
+	Type string `xml:"type,attr,omitempty"`
+	Unknown
+}
+
+// Executable is a source file with its compiled binary
+type Executable struct {
+	Source File  `xml:"source"`
+	Binary *File `xml:"binary,omitempty"`
+	Unknown
+}
+
+// Assets are the checker, the interactor, the validators and the solutions
+type Assets struct {
+	Checker    *Checker    `xml:"checker,omitempty"`
+	Interactor *Interactor `xml:"interactor,omitempty"`
+	Validators []Validator `xml:"validators>validator"`
+	Solutions  []Solution  `xml:"solutions>solution"`
+	Unknown
+}
+
+// Checker of the problem. Name is set for standard checkers, such as "std::ncmp.cpp".
+type Checker struct {
+	Name    string   `xml:"name,attr,omitempty"`
+	Type    string   `xml:"type,attr,omitempty"`
+	Source  File     `xml:"source"`
+	Binary  *File    `xml:"binary,omitempty"`
+	Copy    *File    `xml:"copy,omitempty"`
+	Testset *Testset `xml:"testset,omitempty"`
+	Unknown
+}
+
+// Interactor of an interactive problem
+type Interactor struct {
+	Source File  `xml:"source"`
+	Binary *File `xml:"binary,omitempty"`
+	Unknown
+}
+
+// Validator of the tests, with its own tests
+type Validator struct {
+	Source  File     `xml:"source"`
+	Binary  *File    `xml:"binary,omitempty"`
+	Testset *Testset `xml:"testset,omitempty"`
+	Unknown
+}
+
+// Solution of the problem
+//
+// Tag : "main", "accepted", "rejected", "wrong-answer", "time-limit-exceeded", ...
+type Solution struct {
+	Tag    string `xml:"tag,attr"`
+	Source File   `xml:"source"`
+	Binary *File  `xml:"binary,omitempty"`
+	Unknown
+}
+
+// Property is a named property of the problem, such as tests-wellformed
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// Stresses describes the stress tests of the problem, List is kept as raw XML
+type Stresses struct {
+	StressCount       int    `xml:"stress-count"`
+	StressPathPattern string `xml:"stress-path-pattern"`
+	List              RawXML `xml:"list"`
+	Unknown
+}
+
+// RawXML is the unparsed content of an element
+type RawXML struct {
+	Content string `xml:",innerxml"`
+}
+
+// Material is an additional file of the package, such as an attachment published with the statement
+type Material struct {
+	Path    string `xml:"path,attr"`
+	Publish string `xml:"publish,attr,omitempty"`
+	Unknown
+}
+
+// RawElement is an element unknown to this package, kept as raw XML
+type RawElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Content string     `xml:",innerxml"`
+}
+
+// Unknown holds the attributes and the child elements of an element that this package
+// does not model, such as those added by newer versions of polygon, so that they are
+// written back by Write
+type Unknown struct {
+	UnknownAttrs    []xml.Attr   `xml:",any,attr"`
+	UnknownElements []RawElement `xml:",any"`
+}
+
+// Tag is a tag of the problem
+type Tag struct {
+	Value string `xml:"value,attr"`
+}
+
+// Parse reads a problem.xml. The attributes and elements which are not modelled
+// are kept in the Unknown fields, so that Write preserves them.
+func Parse(r io.Reader) (problem *Problem, err error) {
+	problem = &Problem{}
+	if err = xml.NewDecoder(r).Decode(problem); err != nil {
+		return nil, fmt.Errorf("problemxml: %v", err)
+	}
+	return problem, nil
+}
+
+// ParseFile reads the problem.xml at path
+func ParseFile(path string) (problem *Problem, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// FromPackage reads the problem.xml of a zipped package, as returned by ProblemPackage
+func FromPackage(packageData []byte) (problem *Problem, err error) {
+	archive, err := zip.NewReader(bytes.NewReader(packageData), int64(len(packageData)))
+	if err != nil {
+		return nil, err
+	}
+	file, err := archive.Open("problem.xml")
+	if err != nil {
+		return nil, fmt.Errorf("problemxml: %v", err)
+	}
+	defer file.Close()
+	return Parse(file)
+}
+
+// Write writes the problem as an indented problem.xml
+func (problem *Problem) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "    ")
+	if err := encoder.Encode(problem); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteFile writes the problem to path
+func (problem *Problem) WriteFile(path string) error {
+	var buffer bytes.Buffer
+	if err := problem.Write(&buffer); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+// Name returns the name of the problem in a language, or "" if it has none
+func (problem *Problem) Name(language string) string {
+	for _, name := range problem.Names {
+		if name.Language == language {
+			return name.Value
+		}
+	}
+	return ""
+}
+
+// Testset returns the testset of the given name, or nil
+func (problem *Problem) Testset(name string) *Testset {
+	for i := range problem.Judging.Testsets {
+		if problem.Judging.Testsets[i].Name == name {
+			return &problem.Judging.Testsets[i]
+		}
+	}
+	return nil
+}
+
+// MainSolution returns the solution tagged main, or nil
+func (problem *Problem) MainSolution() *Solution {
+	for i := range problem.Assets.Solutions {
+		if problem.Assets.Solutions[i].Tag == "main" {
+			return &problem.Assets.Solutions[i]
+		}
+	}
+	return nil
+}
+
+// Property returns the value of a property, or "" if it is not set
+func (problem *Problem) Property(name string) string {
+	for _, property := range problem.Properties {
+		if property.Name == name {
+			return property.Value
+		}
+	}
+	return ""
+}
+
+// InputPath returns the path of the input of a test in the package, indices start at 1
+func (testset *Testset) InputPath(index int) string {
+	return fmt.Sprintf(testset.InputPathPattern, index)
+}
+
+// AnswerPath returns the path of the answer of a test in the package, indices start at 1
+func (testset *Testset) AnswerPath(index int) string {
+	return fmt.Sprintf(testset.AnswerPathPattern, index)
+}
+
+// Group returns the group of the given name, or nil
+func (testset *Testset) Group(name string) *Group {
+	for i := range testset.Groups {
+		if testset.Groups[i].Name == name {
+			return &testset.Groups[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package problemxml
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// roundTrip writes a problem and parses it again
+func roundTrip(t *testing.T, problem *Problem) (written string, again *Problem) {
+	var buffer bytes.Buffer
+	if err := problem.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	again, err := Parse(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		t.Fatalf("%v in\n%s", err, buffer.String())
+	}
+	return buffer.String(), again
+}
+
+func TestRoundTrip(t *testing.T) {
+	problem, err := ParseFile("testdata/problem.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if problem.Name("english") != "Subtask Sum" || problem.MainSolution().Source.Path != "solutions/sol.cpp" {
+		t.Errorf("got name %q and main solution %+v", problem.Name("english"), problem.MainSolution())
+	}
+	tests := problem.Testset("tests")
+	if tests == nil || len(tests.Tests) != 4 || tests.Tests[0].Points == nil || *tests.Tests[0].Points != 0 {
+		t.Fatalf("got testset %+v, want 4 tests worth 0 points", tests)
+	}
+	if group := tests.Group("0"); group == nil || group.Points == nil || *group.Points != 0 {
+		t.Errorf("got group %+v, want a group worth 0 points", group)
+	}
+
+	written, again := roundTrip(t, problem)
+	if !reflect.DeepEqual(again, problem) {
+		t.Errorf("round trip changed the problem:\n%s", written)
+	}
+	for _, kept := range []string{`points="0"`, `<documents>`, `<document path="documents/description.txt" type="text/plain"/>`} {
+		if !strings.Contains(written, kept) {
+			t.Errorf("%s is missing from\n%s", kept, written)
+		}
+	}
+	if rewritten, _ := roundTrip(t, again); rewritten != written {
+		t.Errorf("writing twice differs:\n%s\nthen\n%s", written, rewritten)
+	}
+}
+
+func TestUnknownAttributes(t *testing.T) {
+	source := `<problem revision="1" short-name="a" future="yes"><judging input-file="" output-file="" grader="g.cpp"><testset name="tests"><test-count>0</test-count><input-path-pattern>tests/%02d</input-path-pattern><scoring mode="max"/></testset></judging></problem>`
+	problem, err := Parse(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, again := roundTrip(t, problem)
+	if !reflect.DeepEqual(again, problem) {
+		t.Errorf("round trip changed the problem:\n%s", written)
+	}
+	for _, kept := range []string{`future="yes"`, `grader="g.cpp"`, `<scoring mode="max"></scoring>`} {
+		if !strings.Contains(written, kept) {
+			t.Errorf("%s is missing from\n%s", kept, written)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package polygon
+
+import (
+	"sync"
+	"time"
+)
+
+// isRetryable reports whether a failed call is worth retrying
+func isRetryable(err error) bool {
+	switch ErrorClass(err) {
+	case ErrorClassTransport, ErrorClassServer, ErrorClassRateLimit:
+		return true
+	}
+	return false
+}
+
+// RetryInterceptor returns an Interceptor that retries calls failing with a transport,
+// server or rate limit error, up to attempts times in total.
+// The delay between attempts starts at backoff and doubles after each retry.
+//
+// Every retry is reported to metrics, which may be nil.
+//
+// Note that a save request may have been applied by polygon even if its reply was lost,
+// so retried saves with checkExisting=true can fail on the second attempt.
+func RetryInterceptor(attempts int, backoff time.Duration, metrics Metrics) Interceptor {
+	return func(request *Request, next Handler) (response *Response, err error) {
+		delay := backoff
+		for attempt := 1; ; attempt++ {
+			response, err = next(request)
+			if err == nil || attempt >= attempts || !isRetryable(err) {
+				return response, err
+			}
+
+			if metrics != nil {
+				metrics.ObserveRetry(request.Method)
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+}
+
+// pacer spaces events by at least interval, even when they come from several goroutines
+type pacer struct {
+	mu       sync.Mutex
+	interval time.Duration
+	nextSlot time.Time
+}
+
+// reserve books the next slot and returns how long to wait for it
+func (p *pacer) reserve() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	wait := p.nextSlot.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	p.nextSlot = now.Add(wait + p.interval)
+	return wait
+}
+
+// RateLimitInterceptor returns an Interceptor that spaces the start of consecutive calls
+// by at least interval, even when they are made from several goroutines.
+//
+// Every delayed call is reported to metrics, which may be nil.
+func RateLimitInterceptor(interval time.Duration, metrics Metrics) Interceptor {
+	limiter := &pacer{interval: interval}
+
+	return func(request *Request, next Handler) (response *Response, err error) {
+		if wait := limiter.reserve(); wait > 0 {
+			if metrics != nil {
+				metrics.ObserveRateLimitWait(request.Method, wait)
+			}
+			time.Sleep(wait)
+		}
+		return next(request)
+	}
+}
@@ -0,0 +1,82 @@
+package script
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Change kinds, as reported in Change.Kind
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a test generated differently by two revisions of a script.
+// Old is nil for an added test, New is nil for a removed test.
+type Change struct {
+	Index int
+	Kind  string
+	Old   *Command
+	New   *Command
+}
+
+func (change Change) String() string {
+	switch change.Kind {
+	case Added:
+		return fmt.Sprintf("+ %d: %s", change.Index, change.New.CommandLine())
+	case Removed:
+		return fmt.Sprintf("- %d: %s", change.Index, change.Old.CommandLine())
+	}
+	return fmt.Sprintf("~ %d: %s -> %s", change.Index, change.Old.CommandLine(), change.New.CommandLine())
+}
+
+// generatedBy maps each test index to the command generating it
+func (script *Script) generatedBy(reserved []int) map[int]*Command {
+	commands := make(map[int]*Command)
+	for _, assignment := range script.Resolve(reserved) {
+		command := assignment.Command
+		for _, index := range assignment.Indices {
+			if _, ok := commands[index]; !ok {
+				commands[index] = &command
+			}
+		}
+	}
+	return commands
+}
+
+// Diff compares two revisions of a script test by test, after resolving $ with the same
+// reserved indices. Spacing and quoting make no difference, nor does reordering lines with
+// explicit targets, only tests which are added, removed or generated by another command line
+// are reported, by index. Since $ takes the first free index in script order, reordering
+// lines targeting $ swaps their indices and is reported as changed tests.
+func Diff(old, new *Script, reserved []int) (changes []Change) {
+	before, after := old.generatedBy(reserved), new.generatedBy(reserved)
+
+	for index, command := range before {
+		if other, ok := after[index]; !ok {
+			changes = append(changes, Change{Index: index, Kind: Removed, Old: command})
+		} else if other.CommandLine() != command.CommandLine() {
+			changes = append(changes, Change{Index: index, Kind: Changed, Old: command, New: other})
+		}
+	}
+	for index, command := range after {
+		if _, ok := before[index]; !ok {
+			changes = append(changes, Change{Index: index, Kind: Added, New: command})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Index < changes[j].Index })
+	return changes
+}
+
+// WriteDiff prints one change per line, prefixed by +, - or ~
+func WriteDiff(w io.Writer, changes []Change) error {
+	for _, change := range changes {
+		if _, err := fmt.Fprintln(w, change.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
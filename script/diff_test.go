@@ -0,0 +1,43 @@
+package script
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		reserved []int
+		want     string
+	}{
+		{"identical", "gen 1 > 1\ngen 2 > $", "gen 1 > 1\ngen 2 > $", nil, ""},
+		{"spacing and quoting", "gen 1 > 1", `  "gen"   "1"  >  1`, nil, ""},
+		{"reordered explicit lines", "gen 1 > 1\ngen 2 > 2", "gen 2 > 2\ngen 1 > 1", nil, ""},
+		{"reordered auto lines", "gen 1 > $\ngen 2 > $", "gen 2 > $\ngen 1 > $", nil, "~ 1: gen 1 -> gen 2\n~ 2: gen 2 -> gen 1\n"},
+		{"added", "gen 1 > 1", "gen 1 > 1\ngen 2 > {2-3}", nil, "+ 2: gen 2\n+ 3: gen 2\n"},
+		{"removed", "gen 1 > 1\ngen 2 > $", "gen 1 > 1", nil, "- 2: gen 2\n"},
+		{"changed", "gen 1 > 1", "gen 1 x > 1", nil, "~ 1: gen 1 -> gen 1 x\n"},
+		{"reserved", "gen 1 > $", "gen 1 > $", []int{1}, ""},
+		{"auto shifted by an explicit target", "gen 1 > $", "gen 2 > 1\ngen 1 > $", nil, "~ 1: gen 1 -> gen 2\n+ 2: gen 1\n"},
+	}
+	for _, test := range tests {
+		old, err := Parse(test.old)
+		if err != nil {
+			t.Fatal(err)
+		}
+		new, err := Parse(test.new)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out bytes.Buffer
+		if err = WriteDiff(&out, Diff(old, new, test.reserved)); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != test.want {
+			t.Errorf("%s: got diff\n%s\nwant\n%s", test.name, out.String(), test.want)
+		}
+	}
+}
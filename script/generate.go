@@ -0,0 +1,167 @@
+package script
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/judge"
+)
+
+// Config holds the settings of Generate
+//
+// Dir            : directory receiving the generated inputs, one file per test named after its index
+//
+// Languages      : how to compile each source type, defaults to judge.DefaultLanguages
+//
+// Reserved       : indices of manual tests, never taken by $
+//
+// Limits         : limits of each generator run, defaults to 10 seconds of CPU time
+//
+// CompileTimeout : time allowed to each compilation, defaults to one minute
+type Config struct {
+	Dir            string
+	Languages      map[string]judge.Language
+	Reserved       []int
+	Limits         judge.Limits
+	CompileTimeout time.Duration
+}
+
+// GeneratedTest is an input written by Generate
+type GeneratedTest struct {
+	Index   int
+	Path    string
+	Command Command
+}
+
+// GeneratorError reports a generator which failed on a command
+type GeneratorError struct {
+	Command Command
+	Usage   judge.Usage
+	Stderr  string
+}
+
+func (e *GeneratorError) Error() string {
+	return fmt.Sprintf("script: line %d: %q failed with exit code %d: %s",
+		e.Command.Line, e.Command.CommandLine(), e.Usage.ExitCode, strings.TrimSpace(e.Stderr))
+}
+
+// compileGenerators compiles every generator used by the script from the source files of the problem
+func compileGenerators(client polygon.ProblemReader, script *Script, dir string, config Config) (programs map[string]*judge.Program, err error) {
+	files, err := client.ProblemFiles(map[string]string{})
+	if err != nil {
+		return programs, err
+	}
+	sources := make(map[string]polygon.FileObject)
+	for _, file := range files.SourceFiles {
+		sources[strings.TrimSuffix(file.Name, filepath.Ext(file.Name))] = file
+	}
+
+	headers, err := judge.FetchHeaders(client)
+	if err != nil {
+		return programs, err
+	}
+
+	programs = make(map[string]*judge.Program)
+	for _, command := range script.Commands {
+		if _, ok := programs[command.Generator]; ok {
+			continue
+		}
+		file, ok := sources[command.Generator]
+		if !ok {
+			return programs, fmt.Errorf("script: line %d: no source file for generator %s", command.Line, command.Generator)
+		}
+
+		content, err := client.ProblemViewFile(map[string]string{"type": "source", "name": file.Name})
+		if err != nil {
+			return programs, err
+		}
+		source := judge.Source{Name: file.Name, Type: file.SourceType, Content: content, Includes: headers}
+		program, err := judge.Compile(filepath.Join(dir, command.Generator), config.Languages, source, config.CompileTimeout)
+		if err != nil {
+			return programs, err
+		}
+		programs[command.Generator] = program
+	}
+	return programs, nil
+}
+
+// run executes a command and moves the inputs it generates to config.Dir.
+// A generator writing a single test prints it, a generator writing several tests creates
+// files in its working directory, named after the test indices or numbered from 1.
+func run(program *judge.Program, assignment Assignment, dir string, config Config) (tests []GeneratedTest, err error) {
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return tests, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	usage, err := judge.Execute(program, assignment.Command.Args, dir, nil, &stdout, &stderr, config.Limits)
+	if err != nil {
+		return tests, err
+	}
+	if usage.ExitCode != 0 || usage.Killed {
+		return tests, &GeneratorError{Command: assignment.Command, Usage: usage, Stderr: stderr.String()}
+	}
+
+	for i, index := range assignment.Indices {
+		path := filepath.Join(config.Dir, strconv.Itoa(index))
+		if len(assignment.Indices) == 1 {
+			err = ioutil.WriteFile(path, stdout.Bytes(), 0644)
+		} else {
+			produced := filepath.Join(dir, strconv.Itoa(index))
+			if _, statErr := os.Stat(produced); statErr != nil {
+				produced = filepath.Join(dir, strconv.Itoa(i+1))
+			}
+			err = os.Rename(produced, path)
+		}
+		if err != nil {
+			return tests, fmt.Errorf("script: line %d: test %d was not generated: %v", assignment.Command.Line, index, err)
+		}
+		tests = append(tests, GeneratedTest{Index: index, Path: path, Command: assignment.Command})
+	}
+	return tests, nil
+}
+
+// Generate compiles the generators of the script from the source files of the problem,
+// and runs every command to produce the test inputs locally in config.Dir.
+func Generate(client polygon.ProblemReader, script *Script, config Config) (tests []GeneratedTest, err error) {
+	if config.Languages == nil {
+		config.Languages = judge.DefaultLanguages
+	}
+	if config.Limits.Time == 0 {
+		config.Limits.Time = 10 * time.Second
+	}
+	if config.CompileTimeout == 0 {
+		config.CompileTimeout = time.Minute
+	}
+	if err = os.MkdirAll(config.Dir, 0755); err != nil {
+		return tests, err
+	}
+
+	workDir, err := ioutil.TempDir("", "polygon-script-")
+	if err != nil {
+		return tests, err
+	}
+	defer os.RemoveAll(workDir)
+
+	programs, err := compileGenerators(client, script, filepath.Join(workDir, "generators"), config)
+	if err != nil {
+		return tests, err
+	}
+
+	for i, assignment := range script.Resolve(config.Reserved) {
+		dir := filepath.Join(workDir, "runs", strconv.Itoa(i))
+		generated, err := run(programs[assignment.Command.Generator], assignment, dir, config)
+		if err != nil {
+			return tests, err
+		}
+		tests = append(tests, generated...)
+	}
+	return tests, nil
+}
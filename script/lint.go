@@ -0,0 +1,170 @@
+package script
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+)
+
+// Lint rules, as reported in Issue.Rule
+const (
+	RuleDuplicateIndex   = "duplicate-index"
+	RuleManualOverlap    = "manual-overlap"
+	RuleMissingGenerator = "missing-generator"
+	RuleDuplicateCommand = "duplicate-command"
+	RuleIndexGap         = "index-gap"
+)
+
+// Issue is a problem found in a script by Lint
+//
+// Line    : line of the offending command, 0 if it is not tied to a command
+//
+// Rule    : one of the Rule* constants
+//
+// Message : human readable explanation
+type Issue struct {
+	Line    int
+	Rule    string
+	Message string
+}
+
+func (issue Issue) String() string {
+	return fmt.Sprintf("line %d: %s: %s", issue.Line, issue.Rule, issue.Message)
+}
+
+// formatIndices formats sorted indices as ranges, e.g. "3, 7-9"
+func formatIndices(indices []int) string {
+	var parts []string
+	for i := 0; i < len(indices); {
+		j := i
+		for j+1 < len(indices) && indices[j+1] == indices[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, fmt.Sprint(indices[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", indices[i], indices[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Lint checks a script against the indices of the manual tests and the names of the
+// source files of the problem (with or without extension). It reports explicit indices used twice
+// or taken by a manual test, generators without a source file, identical command lines
+// (which generate identical tests) and indices missing between 1 and the last test.
+func Lint(script *Script, manual []int, sourceFiles []string) (issues []Issue) {
+	isManual := make(map[int]bool)
+	for _, index := range manual {
+		isManual[index] = true
+	}
+	generators := make(map[string]bool)
+	for _, name := range sourceFiles {
+		generators[name] = true
+		generators[strings.TrimSuffix(name, filepath.Ext(name))] = true
+	}
+
+	definedAt := make(map[int]int)
+	commandAt := make(map[string]int)
+	reported := make(map[string]bool)
+	for _, command := range script.Commands {
+		for _, index := range command.Target.Indices() {
+			if isManual[index] {
+				issues = append(issues, Issue{command.Line, RuleManualOverlap,
+					fmt.Sprintf("test %d is a manual test", index)})
+			}
+			if line, ok := definedAt[index]; ok {
+				issues = append(issues, Issue{command.Line, RuleDuplicateIndex,
+					fmt.Sprintf("test %d is already generated on line %d", index, line)})
+				continue
+			}
+			definedAt[index] = command.Line
+		}
+
+		if !generators[command.Generator] && !reported[command.Generator] {
+			reported[command.Generator] = true
+			issues = append(issues, Issue{command.Line, RuleMissingGenerator,
+				fmt.Sprintf("generator %s is not among the source files", command.Generator)})
+		}
+
+		commandLine := command.CommandLine()
+		if line, ok := commandAt[commandLine]; ok {
+			issues = append(issues, Issue{command.Line, RuleDuplicateCommand,
+				fmt.Sprintf("same command as line %d, the tests are identical", line)})
+		} else {
+			commandAt[commandLine] = command.Line
+		}
+	}
+
+	used := make(map[int]int)
+	last := 0
+	for _, index := range manual {
+		used[index] = 0
+		if index > last {
+			last = index
+		}
+	}
+	for _, assignment := range script.Resolve(manual) {
+		for _, index := range assignment.Indices {
+			if _, ok := used[index]; !ok {
+				used[index] = assignment.Command.Line
+			}
+			if index > last {
+				last = index
+			}
+		}
+	}
+	var gap []int
+	for index := 1; index <= last; index++ {
+		if _, ok := used[index]; !ok {
+			gap = append(gap, index)
+			continue
+		}
+		if len(gap) > 0 {
+			issues = append(issues, Issue{used[index], RuleIndexGap,
+				fmt.Sprintf("tests %s are missing", formatIndices(gap))})
+			gap = nil
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// LintProblem fetches the script, the manual tests and the source files of the problem
+// for the given testset, and lints the script.
+func LintProblem(client polygon.ProblemReader, testset string) (issues []Issue, err error) {
+	source, err := client.ProblemScript(map[string]string{"testset": testset})
+	if err != nil {
+		return issues, err
+	}
+	script, err := Parse(source)
+	if err != nil {
+		return issues, err
+	}
+
+	tests, err := client.ProblemTests(map[string]string{"testset": testset, "noInputs": "true"})
+	if err != nil {
+		return issues, err
+	}
+	var manual []int
+	for _, test := range tests {
+		if test.Manual {
+			manual = append(manual, test.Index)
+		}
+	}
+
+	files, err := client.ProblemFiles(map[string]string{})
+	if err != nil {
+		return issues, err
+	}
+	var sourceFiles []string
+	for _, file := range files.SourceFiles {
+		sourceFiles = append(sourceFiles, file.Name)
+	}
+	return Lint(script, manual, sourceFiles), nil
+}
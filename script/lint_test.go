@@ -0,0 +1,75 @@
+package script
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		manual  []int
+		sources []string
+		want    []Issue
+	}{
+		{"clean", "gen 1 > 2\ngen 2 > $", []int{1}, []string{"gen.cpp"}, nil},
+		{
+			"duplicate index",
+			"gen 1 > 1\ngen 2 > {1-2}",
+			nil, []string{"gen"},
+			[]Issue{{2, RuleDuplicateIndex, "test 1 is already generated on line 1"}},
+		},
+		{
+			"manual overlap",
+			"gen 1 > 1",
+			[]int{1}, []string{"gen.cpp"},
+			[]Issue{{1, RuleManualOverlap, "test 1 is a manual test"}},
+		},
+		{
+			"missing generator reported once",
+			"gen 1 > 1\ngen 2 > 2",
+			nil, []string{"other.cpp"},
+			[]Issue{{1, RuleMissingGenerator, "generator gen is not among the source files"}},
+		},
+		{
+			"duplicate command",
+			"gen 1 > 1\n  gen  \"1\" > 2",
+			nil, []string{"gen.cpp"},
+			[]Issue{{2, RuleDuplicateCommand, "same command as line 1, the tests are identical"}},
+		},
+		{
+			"index gap",
+			"gen 1 > 1\ngen 2 > 4\ngen 3 > 9",
+			[]int{5}, []string{"gen.cpp"},
+			[]Issue{{2, RuleIndexGap, "tests 2-3 are missing"}, {3, RuleIndexGap, "tests 6-8 are missing"}},
+		},
+		{"auto fills the gaps", "gen 1 > 3\ngen 2 > $\ngen 3 > $", nil, []string{"gen.cpp"}, nil},
+	}
+	for _, test := range tests {
+		script, err := Parse(test.source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Lint(script, test.manual, test.sources); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Lint() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFormatIndices(t *testing.T) {
+	tests := []struct {
+		indices []int
+		want    string
+	}{
+		{nil, ""},
+		{[]int{3}, "3"},
+		{[]int{3, 7, 8, 9}, "3, 7-9"},
+		{[]int{1, 2, 4, 5}, "1-2, 4-5"},
+	}
+	for _, test := range tests {
+		if got := formatIndices(test.indices); got != test.want {
+			t.Errorf("formatIndices(%v) = %q, want %q", test.indices, got, test.want)
+		}
+	}
+}
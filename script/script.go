@@ -0,0 +1,250 @@
+// Package script parses, formats and runs polygon test generation scripts,
+// as returned by ProblemScript.
+//
+// A script has one command per line, such as
+//
+//	gen 10 20 > 1
+//	gen 5 > $
+//	multigen 3 > {4-6}
+//
+// where $ picks the first free test index. Freemarker directives (<#list ...>) are not supported.
+package script
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range is an inclusive range of test indices
+type Range struct {
+	From int
+	To   int
+}
+
+// Target is the right hand side of a command: the tests it generates.
+// Either Auto is set ($), or Ranges lists the indices.
+type Target struct {
+	Auto   bool
+	Ranges []Range
+}
+
+// Indices returns the explicit indices of the target, in order
+func (target Target) Indices() (indices []int) {
+	for _, r := range target.Ranges {
+		for index := r.From; index <= r.To; index++ {
+			indices = append(indices, index)
+		}
+	}
+	return indices
+}
+
+// String formats the target as in a script: $, 5 or {1,3-5}
+func (target Target) String() string {
+	if target.Auto {
+		return "$"
+	}
+	if len(target.Ranges) == 1 && target.Ranges[0].From == target.Ranges[0].To {
+		return strconv.Itoa(target.Ranges[0].From)
+	}
+
+	parts := make([]string, len(target.Ranges))
+	for i, r := range target.Ranges {
+		parts[i] = strconv.Itoa(r.From)
+		if r.To != r.From {
+			parts[i] += "-" + strconv.Itoa(r.To)
+		}
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// Command is a line of the script running a generator
+//
+// Line      : line number in the parsed source, starting at 1 (0 for commands built in code)
+//
+// Generator : name of the generator, the name of a source file without its extension
+//
+// Args      : arguments passed to the generator
+//
+// Target    : tests written by the generator
+type Command struct {
+	Line      int
+	Generator string
+	Args      []string
+	Target    Target
+}
+
+// CommandLine returns the generator invocation without its target, e.g. "gen 10 20"
+func (command Command) CommandLine() string {
+	words := []string{quote(command.Generator)}
+	for _, arg := range command.Args {
+		words = append(words, quote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// String formats the command as a line of a script
+func (command Command) String() string {
+	return command.CommandLine() + " > " + command.Target.String()
+}
+
+// Script is a parsed generation script
+type Script struct {
+	Commands []Command
+}
+
+// String formats the script, one command per line.
+// Parsing the result gives back the same commands.
+func (script *Script) String() string {
+	var b strings.Builder
+	for _, command := range script.Commands {
+		b.WriteString(command.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// SyntaxError reports an invalid line of a script
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("script: line %d: %s", e.Line, e.Message)
+}
+
+// quote returns an argument as it must be written in a script
+func quote(arg string) string {
+	if arg == "" || strings.ContainsAny(arg, " \t\"'>") {
+		return strconv.Quote(arg)
+	}
+	return arg
+}
+
+// splitWords splits a command line into words, honouring double quoted words
+func splitWords(text string) (words []string, err error) {
+	for text = strings.TrimSpace(text); text != ""; text = strings.TrimSpace(text) {
+		if text[0] != '"' {
+			end := strings.IndexAny(text, " \t")
+			if end < 0 {
+				end = len(text)
+			}
+			words = append(words, text[:end])
+			text = text[end:]
+			continue
+		}
+
+		prefix, err := strconv.QuotedPrefix(text)
+		if err != nil {
+			return words, fmt.Errorf("unterminated quoted argument")
+		}
+		word, _ := strconv.Unquote(prefix)
+		words = append(words, word)
+		text = text[len(prefix):]
+	}
+	return words, nil
+}
+
+// parseTarget parses $, 5 or {1,3-5}
+func parseTarget(text string) (target Target, err error) {
+	if text == "$" {
+		return Target{Auto: true}, nil
+	}
+	if index, err := strconv.Atoi(text); err == nil && index > 0 {
+		return Target{Ranges: []Range{{From: index, To: index}}}, nil
+	}
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return target, fmt.Errorf("invalid target %q, expected $, an index or {ranges}", text)
+	}
+
+	for _, part := range strings.Split(text[1:len(text)-1], ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		from, err := strconv.Atoi(strings.TrimSpace(bounds[0]))
+		if err != nil || from <= 0 {
+			return target, fmt.Errorf("invalid index %q in target %s", bounds[0], text)
+		}
+		to := from
+		if len(bounds) == 2 {
+			to, err = strconv.Atoi(strings.TrimSpace(bounds[1]))
+			if err != nil || to < from {
+				return target, fmt.Errorf("invalid range %q in target %s", part, text)
+			}
+		}
+		target.Ranges = append(target.Ranges, Range{From: from, To: to})
+	}
+	return target, nil
+}
+
+// Parse parses a generation script. Blank lines are ignored.
+func Parse(source string) (script *Script, err error) {
+	script = &Script{}
+	for number, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "<#") || strings.HasPrefix(line, "</#") {
+			return nil, &SyntaxError{Line: number + 1, Message: "freemarker directives are not supported"}
+		}
+
+		separator := strings.LastIndex(line, ">")
+		if separator < 0 {
+			return nil, &SyntaxError{Line: number + 1, Message: "missing target, expected \"generator args > target\""}
+		}
+		words, err := splitWords(line[:separator])
+		if err != nil {
+			return nil, &SyntaxError{Line: number + 1, Message: err.Error()}
+		}
+		if len(words) == 0 {
+			return nil, &SyntaxError{Line: number + 1, Message: "missing generator"}
+		}
+		target, err := parseTarget(strings.TrimSpace(line[separator+1:]))
+		if err != nil {
+			return nil, &SyntaxError{Line: number + 1, Message: err.Error()}
+		}
+
+		script.Commands = append(script.Commands, Command{
+			Line:      number + 1,
+			Generator: words[0],
+			Args:      words[1:],
+			Target:    target,
+		})
+	}
+	return script, nil
+}
+
+// Assignment is a command with the test indices it generates
+type Assignment struct {
+	Command Command
+	Indices []int
+}
+
+// Resolve assigns test indices to every command. Explicit indices are kept,
+// and each $ takes the smallest index not used by reserved (e.g. manual tests),
+// an explicit target, or a previous $.
+func (script *Script) Resolve(reserved []int) (assignments []Assignment) {
+	used := make(map[int]bool)
+	for _, index := range reserved {
+		used[index] = true
+	}
+	for _, command := range script.Commands {
+		for _, index := range command.Target.Indices() {
+			used[index] = true
+		}
+	}
+
+	next := 1
+	for _, command := range script.Commands {
+		indices := command.Target.Indices()
+		if command.Target.Auto {
+			for used[next] {
+				next++
+			}
+			used[next] = true
+			indices = []int{next}
+		}
+		assignments = append(assignments, Assignment{Command: command, Indices: indices})
+	}
+	return assignments
+}
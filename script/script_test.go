@@ -0,0 +1,119 @@
+package script
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   []Command
+		line   int
+	}{
+		{
+			name:   "commands",
+			source: "gen 10 20 > 1\n\n  gen 5 > $\nmultigen 3 > {4-6,8}\n",
+			want: []Command{
+				{Line: 1, Generator: "gen", Args: []string{"10", "20"}, Target: Target{Ranges: []Range{{1, 1}}}},
+				{Line: 3, Generator: "gen", Args: []string{"5"}, Target: Target{Auto: true}},
+				{Line: 4, Generator: "multigen", Args: []string{"3"}, Target: Target{Ranges: []Range{{4, 6}, {8, 8}}}},
+			},
+		},
+		{
+			name:   "quoted arguments",
+			source: `gen "a b" "" "x > y" > 2`,
+			want:   []Command{{Line: 1, Generator: "gen", Args: []string{"a b", "", "x > y"}, Target: Target{Ranges: []Range{{2, 2}}}}},
+		},
+		{
+			name:   "quoted generator",
+			source: `"g h" x > 1`,
+			want:   []Command{{Line: 1, Generator: "g h", Args: []string{"x"}, Target: Target{Ranges: []Range{{1, 1}}}}},
+		},
+		{name: "missing target", source: "gen 1\n", line: 1},
+		{name: "missing generator", source: "gen > 1\n > 2", line: 2},
+		{name: "invalid target", source: "gen > 0", line: 1},
+		{name: "invalid range", source: "gen > {5-3}", line: 1},
+		{name: "unterminated quote", source: `gen "a > 1`, line: 1},
+		{name: "freemarker", source: "<#list 1..5 as i>\ngen ${i} > $\n</#list>", line: 1},
+	}
+	for _, test := range tests {
+		script, err := Parse(test.source)
+		if test.line != 0 {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) || syntaxErr.Line != test.line {
+				t.Errorf("%s: got error %v, want a syntax error on line %d", test.name, err, test.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(script.Commands, test.want) {
+			t.Errorf("%s: got %+v, want %+v", test.name, script.Commands, test.want)
+		}
+	}
+}
+
+func TestParseStringRoundTrip(t *testing.T) {
+	sources := []string{
+		"gen 10 20 > 1\ngen 5 > $\nmultigen 3 > {4-6,8}\n",
+		`gen "a b" "" "x > y" 'q' > 2`,
+		`"" > 1`,
+		`"g h" x > 1`,
+		`"g>h" "\"" > {1-2}`,
+	}
+	for _, source := range sources {
+		script, err := Parse(source)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", source, err)
+			continue
+		}
+		again, err := Parse(script.String())
+		if err != nil {
+			t.Errorf("Parse(%q): %v", script.String(), err)
+			continue
+		}
+		for i := range script.Commands {
+			script.Commands[i].Line = 0
+		}
+		for i := range again.Commands {
+			again.Commands[i].Line = 0
+		}
+		if !reflect.DeepEqual(again.Commands, script.Commands) {
+			t.Errorf("%q formatted as %q parses to %+v, want %+v", source, script.String(), again.Commands, script.Commands)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		reserved []int
+		want     [][]int
+	}{
+		{"explicit", "gen > 1\ngen > {2-3}", nil, [][]int{{1}, {2, 3}}},
+		{"auto", "gen > $\ngen > $", nil, [][]int{{1}, {2}}},
+		{"auto skips explicit", "gen > $\ngen > 1\ngen > $", nil, [][]int{{2}, {1}, {3}}},
+		{"auto skips reserved", "gen > $\ngen > $", []int{1, 3}, [][]int{{2}, {4}}},
+		{"auto skips later ranges", "gen > $\nmultigen > {1-2,4}", []int{3}, [][]int{{5}, {1, 2, 4}}},
+	}
+	for _, test := range tests {
+		script, err := Parse(test.source)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		var got [][]int
+		for _, assignment := range script.Resolve(test.reserved) {
+			got = append(got, assignment.Indices)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
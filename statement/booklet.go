@@ -0,0 +1,241 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+)
+
+// BookletConfig holds the settings of LoadBooklet
+//
+// ContestId : id of the polygon contest
+//
+// Language  : language of the statements, defaults to "english"; english statements are used
+// for the problems which are not translated
+//
+// Testset   : testset holding the examples, defaults to "tests"
+//
+// Name, Location, Date : shown on the cover page and in the headers
+//
+// Options   : settings of the HTML renderer
+type BookletConfig struct {
+	ContestId string
+	Language  string
+	Testset   string
+	Name      string
+	Location  string
+	Date      string
+	Options   Options
+}
+
+// BookletProblem is a problem of a booklet
+//
+// Letter   : letter of the problem in the contest
+//
+// Tutorial : tutorial of the statement, or the general tutorial of the problem when it is empty
+type BookletProblem struct {
+	Problem
+	Letter   string
+	Tutorial string
+}
+
+// Booklet holds the statements of the problems of a contest
+//
+// Style : olymp.sty found in the resource files of a problem, "" if there is none
+type Booklet struct {
+	Config   BookletConfig
+	Problems []BookletProblem
+	Style    string
+}
+
+// letter returns the letter of the i-th problem of a contest: A to Z, then A1, B1...
+func letter(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return string(rune('A'+i%26)) + strconv.Itoa(i/26)
+}
+
+// language returns the configured language or the default one
+func (config BookletConfig) language() string {
+	if config.Language == "" {
+		return "english"
+	}
+	return config.Language
+}
+
+// loadBookletProblem reads a problem of a booklet
+func loadBookletProblem(client polygon.ProblemReader, config BookletConfig) (problem BookletProblem, style string, err error) {
+	testset := config.Testset
+	if testset == "" {
+		testset = "tests"
+	}
+	if problem.Info, err = client.ProblemInfo(map[string]string{}); err != nil {
+		return problem, style, err
+	}
+	statements, err := client.ProblemStatements(map[string]string{})
+	if err != nil {
+		return problem, style, err
+	}
+	statement, ok := statements[config.language()]
+	if !ok {
+		if statement, ok = statements["english"]; !ok {
+			return problem, style, errors.New("no statement in " + config.language())
+		}
+	}
+	problem.Statement, problem.Tutorial = statement, statement.Tutorial
+	if strings.TrimSpace(problem.Tutorial) == "" {
+		if problem.Tutorial, err = client.ProblemViewGeneralTutorial(map[string]string{}); err != nil {
+			return problem, style, err
+		}
+	}
+	if problem.Samples, err = Samples(client, testset); err != nil {
+		return problem, style, err
+	}
+	style, _, err = olympStyle(client)
+	return problem, style, err
+}
+
+// LoadBooklet reads the statements of the problems of a contest, lettered in the order returned
+// by ContestProblems, deleted problems being skipped. problemClient returns the client of a
+// problem of the contest, e.g.
+//
+//	func(problem polygon.ProblemObject) polygon.ProblemReader {
+//		return &polygon.PolygonApi{ApiKey: apiKey, Secret: secret, ProblemId: strconv.Itoa(problem.Id)}
+//	}
+func LoadBooklet(contest polygon.ContestReader, problemClient func(problem polygon.ProblemObject) polygon.ProblemReader, config BookletConfig) (*Booklet, error) {
+	problems, err := contest.ContestProblems(map[string]string{"contestId": config.ContestId})
+	if err != nil {
+		return nil, err
+	}
+	booklet := &Booklet{Config: config}
+	for _, object := range problems {
+		if object.Deleted {
+			continue
+		}
+		problem, style, err := loadBookletProblem(problemClient(object), config)
+		if err != nil {
+			return nil, fmt.Errorf("statement: problem %s: %w", object.Name, err)
+		}
+		problem.Letter = letter(len(booklet.Problems))
+		problem.Dir = problem.Letter
+		booklet.Problems = append(booklet.Problems, problem)
+		if booklet.Style == "" {
+			booklet.Style = style
+		}
+	}
+	return booklet, nil
+}
+
+// latexCover renders the cover page of a booklet, listing the problems, the names being escaped
+func (booklet *Booklet) latexCover() string {
+	var b strings.Builder
+	config := booklet.Config
+	b.WriteString("\n\\begin{titlepage}\n\\centering\n\\vspace*{3cm}\n")
+	fmt.Fprintf(&b, "{\\Huge %s\\par}\n\\vspace{1cm}\n{\\Large %s\\par}\n{\\Large %s\\par}\n\\vspace{2cm}\n",
+		texEscaper.Replace(config.Name), texEscaper.Replace(config.Location), texEscaper.Replace(config.Date))
+	b.WriteString("\\begin{tabular}{ll}\n")
+	for _, problem := range booklet.Problems {
+		fmt.Fprintf(&b, "%s & %s \\\\\n", problem.Letter, texEscaper.Replace(problem.Statement.Name))
+	}
+	b.WriteString("\\end{tabular}\n\\end{titlepage}\n")
+	return b.String()
+}
+
+// LaTeX renders the statements as an olymp.sty document with a cover page,
+// compiled next to olymp.sty and the example files written by WriteLaTeX
+func (booklet *Booklet) LaTeX() string {
+	config := booklet.Config
+	body := booklet.latexCover()
+	for _, problem := range booklet.Problems {
+		body += "\n" + problem.LaTeX(config.language())
+	}
+	return latexDocument(config.language(), config.Name, config.Location, config.Date, body)
+}
+
+// TutorialLaTeX renders the tutorials as an olymp.sty document
+func (booklet *Booklet) TutorialLaTeX() string {
+	config := booklet.Config
+	var body strings.Builder
+	for _, problem := range booklet.Problems {
+		fmt.Fprintf(&body, "\n\\begin{tutorial}{%s}\n\n%s\n\n\\end{tutorial}\n", texEscaper.Replace(problem.Statement.Name), strings.TrimSpace(problem.Tutorial))
+	}
+	return latexDocument(config.language(), config.Name, config.Location, config.Date, body.String())
+}
+
+// htmlDocument wraps the body of a booklet into a page loading MathJax
+func (booklet *Booklet) htmlDocument(title, body string) string {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	b.WriteString("<script src=\"https://cdn.jsdelivr.net/npm/mathjax@3/es5/tex-chtml.js\"></script>\n")
+	b.WriteString("</head>\n<body>\n")
+	b.WriteString("<div class=\"cover\">\n<h1>" + html.EscapeString(booklet.Config.Name) + "</h1>\n")
+	b.WriteString("<div class=\"location\">" + html.EscapeString(booklet.Config.Location) + "</div>\n")
+	b.WriteString("<div class=\"date\">" + html.EscapeString(booklet.Config.Date) + "</div>\n<ol class=\"problems\">\n")
+	for _, problem := range booklet.Problems {
+		b.WriteString("<li><a href=\"#problem-" + problem.Letter + "\">" + problem.Letter + ". " + html.EscapeString(problem.Statement.Name) + "</a></li>\n")
+	}
+	b.WriteString("</ol>\n</div>\n" + body + "</body>\n</html>\n")
+	return b.String()
+}
+
+// HTML renders the statements as a standalone HTML page with a cover, math being rendered by MathJax
+func (booklet *Booklet) HTML() string {
+	var body strings.Builder
+	for _, problem := range booklet.Problems {
+		_, _, timeLimit, memoryLimit := limits(problem.Info, booklet.Config.language())
+		statement := problem.Statement
+		statement.Name = problem.Letter + ". " + statement.Name
+
+		body.WriteString("<div class=\"problem\" id=\"problem-" + problem.Letter + "\">\n")
+		body.WriteString("<div class=\"limits\"><div class=\"time-limit\">" + html.EscapeString(timeLimit) + "</div>")
+		body.WriteString("<div class=\"memory-limit\">" + html.EscapeString(memoryLimit) + "</div></div>\n")
+		body.WriteString(HTML(statement, problem.Samples, booklet.Config.Options))
+		body.WriteString("</div>\n")
+	}
+	return booklet.htmlDocument(booklet.Config.Name, body.String())
+}
+
+// TutorialHTML renders the tutorials as a standalone HTML page
+func (booklet *Booklet) TutorialHTML() string {
+	var body strings.Builder
+	for _, problem := range booklet.Problems {
+		body.WriteString("<div class=\"tutorial\" id=\"problem-" + problem.Letter + "\">\n")
+		body.WriteString("<div class=\"title\">" + problem.Letter + ". " + html.EscapeString(problem.Statement.Name) + "</div>\n")
+		body.WriteString(LaTeXToHTML(problem.Tutorial, booklet.Config.Options))
+		body.WriteString("</div>\n")
+	}
+	return booklet.htmlDocument(booklet.Config.Name, body.String())
+}
+
+// WriteLaTeX writes statements.tex and tutorials.tex into dir, with olymp.sty when found
+// and the example files of each problem in a directory named after its letter
+func (booklet *Booklet) WriteLaTeX(dir string) error {
+	files := map[string][]byte{
+		"statements.tex": []byte(booklet.LaTeX()),
+		"tutorials.tex":  []byte(booklet.TutorialLaTeX()),
+	}
+	if booklet.Style != "" {
+		files["olymp.sty"] = []byte(booklet.Style)
+	}
+	if err := writeFiles(dir, files); err != nil {
+		return err
+	}
+	for _, problem := range booklet.Problems {
+		examples := make(map[string][]byte)
+		for _, sample := range problem.Samples {
+			input, output := ExampleFiles(sample.Index)
+			examples[input], examples[output] = []byte(sample.Input), []byte(sample.Output)
+		}
+		if err := writeFiles(filepath.Join(dir, problem.Dir), examples); err != nil {
+			return err
+		}
+	}
+	return nil
+}
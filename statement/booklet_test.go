@@ -0,0 +1,99 @@
+package statement
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/polygonmock"
+)
+
+func TestLetter(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "A1", 27: "B1", 52: "A2"} {
+		if got := letter(i); got != want {
+			t.Errorf("letter(%d) = %s, want %s", i, got, want)
+		}
+	}
+}
+
+// bookletProblem returns a mock of a problem with statements in the given languages,
+// and a tutorial in its statements or only as its general tutorial
+func bookletProblem(name string, languages []string, tutorial string) *polygonmock.Client {
+	return &polygonmock.Client{
+		ProblemInfoFunc: func(map[string]string) (polygon.ProblemInfoObject, error) {
+			return polygon.ProblemInfoObject{TimeLimit: 1000, MemoryLimit: 256}, nil
+		},
+		ProblemStatementsFunc: func(map[string]string) (map[string]polygon.StatementObject, error) {
+			statements := make(map[string]polygon.StatementObject)
+			for _, language := range languages {
+				statements[language] = polygon.StatementObject{Name: name + " (" + language + ")", Legend: "Legend.", Tutorial: tutorial}
+			}
+			return statements, nil
+		},
+		ProblemViewGeneralTutorialFunc: func(map[string]string) (string, error) { return "General tutorial of " + name + ".", nil },
+	}
+}
+
+func TestLoadBooklet(t *testing.T) {
+	var problems []polygon.ProblemObject
+	clients := make(map[int]*polygonmock.Client)
+	for id := 1; id <= 29; id++ {
+		name := "P" + strconv.Itoa(id)
+		problems = append(problems, polygon.ProblemObject{Id: id, Name: name, Deleted: id == 2})
+		clients[id] = bookletProblem(name, []string{"english", "russian"}, "Tutorial of "+name+".")
+	}
+	clients[1] = bookletProblem("A_1 & 100%", []string{"english"}, "")
+
+	contest := &polygonmock.Client{ContestProblemsFunc: func(parameters map[string]string) ([]polygon.ProblemObject, error) {
+		if parameters["contestId"] != "7" {
+			t.Errorf("got contest %q, want 7", parameters["contestId"])
+		}
+		return problems, nil
+	}}
+	config := BookletConfig{ContestId: "7", Language: "russian", Name: "Cup #1", Location: "R&D", Date: "2026_10"}
+	booklet, err := LoadBooklet(contest, func(problem polygon.ProblemObject) polygon.ProblemReader { return clients[problem.Id] }, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(booklet.Problems) != 28 {
+		t.Fatalf("got %d problems, want 28 without the deleted one", len(booklet.Problems))
+	}
+	first, last := booklet.Problems[0], booklet.Problems[27]
+	if first.Letter != "A" || first.Statement.Name != "A_1 & 100% (english)" || first.Tutorial != "General tutorial of A_1 & 100%." {
+		t.Errorf("got first problem %s %q with tutorial %q, want the english statement and the general tutorial", first.Letter, first.Statement.Name, first.Tutorial)
+	}
+	if booklet.Problems[1].Letter != "B" || booklet.Problems[1].Statement.Name != "P3 (russian)" || booklet.Problems[1].Tutorial != "Tutorial of P3." {
+		t.Errorf("got second problem %s %q with tutorial %q", booklet.Problems[1].Letter, booklet.Problems[1].Statement.Name, booklet.Problems[1].Tutorial)
+	}
+	if last.Letter != "B1" || last.Dir != "B1" || last.Statement.Name != "P29 (russian)" {
+		t.Errorf("got last problem %s in %s named %q, want B1", last.Letter, last.Dir, last.Statement.Name)
+	}
+
+	latex, tutorials := booklet.LaTeX(), booklet.TutorialLaTeX()
+	for _, want := range []string{
+		`\contest{Cup \#1}{R\&D}{2026\_10}`,
+		`{\Huge Cup \#1\par}`,
+		`A & A\_1 \& 100\% (english) \\`,
+		`\begin{problem}{A\_1 \& 100\% (english)}`,
+	} {
+		if !strings.Contains(latex, want) {
+			t.Errorf("statements.tex does not contain %s:\n%s", want, latex)
+		}
+	}
+	if want := "\\begin{tutorial}{A\\_1 \\& 100\\% (english)}\n\nGeneral tutorial of A_1 & 100%.\n"; !strings.Contains(tutorials, want) {
+		t.Errorf("tutorials.tex does not contain %s:\n%s", want, tutorials)
+	}
+}
+
+func TestLoadBookletMissingStatement(t *testing.T) {
+	contest := &polygonmock.Client{ContestProblemsFunc: func(map[string]string) ([]polygon.ProblemObject, error) {
+		return []polygon.ProblemObject{{Id: 1, Name: "only-russian"}}, nil
+	}}
+	client := bookletProblem("P", []string{"russian"}, "")
+	_, err := LoadBooklet(contest, func(polygon.ProblemObject) polygon.ProblemReader { return client }, BookletConfig{Language: "german"})
+	if err == nil || !strings.Contains(err.Error(), "only-russian") {
+		t.Errorf("got error %v, want an error naming the problem", err)
+	}
+}
@@ -0,0 +1,283 @@
+package statement
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+)
+
+// Problem is a problem of a LaTeX document
+//
+// Info      : limits and input/output files of the problem
+//
+// Statement : statement in the language of the document
+//
+// Samples   : examples, whose files are written by WriteLaTeX
+//
+// Dir       : directory of the example files relative to the document, "" for the same directory
+type Problem struct {
+	Info      polygon.ProblemInfoObject
+	Statement polygon.StatementObject
+	Samples   []Sample
+	Dir       string
+}
+
+// limitWords are the words used in the header of a problem
+type limitWords struct {
+	stdin, stdout, second, seconds, megabytes string
+}
+
+// languageLimitWords holds the words of the languages supported by olymp.sty, english being the default
+var languageLimitWords = map[string]limitWords{
+	"english":   {"standard input", "standard output", "second", "seconds", "megabytes"},
+	"russian":   {"стандартный ввод", "стандартный вывод", "секунда", "секунды", "мегабайт"},
+	"ukrainian": {"стандартний вхід", "стандартний вихід", "секунда", "секунди", "мегабайт"},
+}
+
+// olympLanguage returns the language option of olymp.sty for a polygon language
+func olympLanguage(language string) string {
+	if _, ok := languageLimitWords[language]; ok {
+		return language
+	}
+	return "english"
+}
+
+// ExampleFiles returns the names of the input and output files of an example, as in polygon packages
+func ExampleFiles(index int) (input, output string) {
+	input = fmt.Sprintf("example.%02d", index)
+	return input, input + ".a"
+}
+
+// limits returns the input and output files, time and memory limits of a problem, in a language
+func limits(info polygon.ProblemInfoObject, language string) (input, output, timeLimit, memoryLimit string) {
+	words := languageLimitWords[olympLanguage(language)]
+	input, output = info.InputFile, info.OutputFile
+	if input == "" || input == "stdin" {
+		input = words.stdin
+	}
+	if output == "" || output == "stdout" {
+		output = words.stdout
+	}
+	unit := words.seconds
+	if info.TimeLimit == 1000 {
+		unit = words.second
+	}
+	timeLimit = strconv.FormatFloat(float64(info.TimeLimit)/1000, 'f', -1, 64) + " " + unit
+	memoryLimit = strconv.Itoa(info.MemoryLimit) + " " + words.megabytes
+	return input, output, timeLimit, memoryLimit
+}
+
+// texEscaper escapes the characters which are special in LaTeX text
+var texEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`~`, `\textasciitilde{}`,
+	`^`, `\textasciicircum{}`,
+	`&`, `\&`,
+	`%`, `\%`,
+	`_`, `\_`,
+	`#`, `\#`,
+	`$`, `\$`,
+	`{`, `\{`,
+	`}`, `\}`,
+)
+
+// LaTeX renders the problem environment of olymp.sty, for a document in the given language.
+// The name and the file names are plain text and are escaped, the sections are already LaTeX.
+func (problem Problem) LaTeX(language string) string {
+	input, output, timeLimit, memoryLimit := limits(problem.Info, language)
+
+	var b strings.Builder
+	statement := problem.Statement
+	fmt.Fprintf(&b, "\\begin{problem}{%s}{%s}{%s}{%s}{%s}{}\n\n",
+		texEscaper.Replace(statement.Name), texEscaper.Replace(input), texEscaper.Replace(output), timeLimit, memoryLimit)
+	b.WriteString(strings.TrimSpace(statement.Legend) + "\n")
+	for _, section := range []struct{ command, content string }{
+		{"\\InputFile", statement.Input},
+		{"\\OutputFile", statement.Output},
+		{"\\Scoring", statement.Scoring},
+	} {
+		if strings.TrimSpace(section.content) != "" {
+			b.WriteString("\n" + section.command + "\n\n" + strings.TrimSpace(section.content) + "\n")
+		}
+	}
+
+	if len(problem.Samples) > 0 {
+		title := "\\Examples"
+		if len(problem.Samples) == 1 {
+			title = "\\Example"
+		}
+		b.WriteString("\n" + title + "\n\n\\begin{example}\n")
+		for _, sample := range problem.Samples {
+			input, output := ExampleFiles(sample.Index)
+			fmt.Fprintf(&b, "\\exmpfile{%s}{%s}%%\n", path.Join(problem.Dir, input), path.Join(problem.Dir, output))
+		}
+		b.WriteString("\\end{example}\n")
+	}
+	if strings.TrimSpace(statement.Notes) != "" {
+		b.WriteString("\n\\Note\n\n" + strings.TrimSpace(statement.Notes) + "\n")
+	}
+	b.WriteString("\n\\end{problem}\n")
+	return b.String()
+}
+
+// latexPreamble is the beginning of a document using olymp.sty, as in the statements of polygon packages
+const latexPreamble = `\documentclass[11pt,a4paper,oneside]{article}
+
+\usepackage[T2A]{fontenc}
+\usepackage[utf8]{inputenc}
+\usepackage[%s]{babel}
+\usepackage{amsmath}
+\usepackage{amssymb}
+\usepackage[%s]{olymp}
+\usepackage{comment}
+\usepackage{epigraph}
+\usepackage{expdlist}
+\usepackage{graphicx}
+\usepackage{ulem}
+\usepackage{import}
+
+\begin{document}
+
+\contest{%s}{%s}{%s}%%
+
+\binoppenalty=10000
+\relpenalty=10000
+
+\renewcommand{\t}{\texttt}
+`
+
+// LaTeXDocument renders a standalone document holding the problems, to be compiled next to olymp.sty
+// and the example files of the problems
+func LaTeXDocument(language string, problems ...Problem) string {
+	var body strings.Builder
+	for _, problem := range problems {
+		body.WriteString("\n" + problem.LaTeX(language))
+	}
+	return latexDocument(language, "", "", "", body.String())
+}
+
+// latexDocument renders a document with the given contest header, escaped, and body
+func latexDocument(language, name, location, date, body string) string {
+	babel := "english"
+	if language != "" && language != "english" {
+		babel += "," + language
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, latexPreamble, babel, olympLanguage(language), texEscaper.Replace(name), texEscaper.Replace(location), texEscaper.Replace(date))
+	b.WriteString(body)
+	b.WriteString("\n\\end{document}\n")
+	return b.String()
+}
+
+// LaTeXConfig holds the settings of WriteLaTeX
+//
+// Testset      : testset holding the examples, defaults to "tests"
+//
+// ReadResource : returns the content of a statement resource, such as an image. The polygon API
+// lists statement resources but does not serve them, so they are reported as missing when nil.
+type LaTeXConfig struct {
+	Testset      string
+	ReadResource func(name string) ([]byte, error)
+}
+
+// writeFiles writes files into dir, creating it
+func writeFiles(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// olympStyle returns olymp.sty from the resource files of the problem, if any
+func olympStyle(client polygon.ProblemReader) (content string, ok bool, err error) {
+	files, err := client.ProblemFiles(map[string]string{})
+	if err != nil {
+		return "", false, err
+	}
+	for _, file := range files.ResourceFiles {
+		if file.Name == "olymp.sty" {
+			content, err = client.ProblemViewFile(map[string]string{"type": "resource", "name": file.Name})
+			return content, err == nil, err
+		}
+	}
+	return "", false, nil
+}
+
+// WriteLaTeX writes, for each language of the problem, a directory dir/<language> holding
+// problem.tex, the example files, olymp.sty taken from the resource files of the problem
+// and the statement resources, so that running pdflatex problem.tex in it builds the statement.
+//
+// The names of the files which could not be written, olymp.sty or statement resources, are returned.
+func WriteLaTeX(client polygon.ProblemReader, dir string, config LaTeXConfig) (missing []string, err error) {
+	testset := config.Testset
+	if testset == "" {
+		testset = "tests"
+	}
+	info, err := client.ProblemInfo(map[string]string{})
+	if err != nil {
+		return missing, err
+	}
+	statements, err := client.ProblemStatements(map[string]string{})
+	if err != nil {
+		return missing, err
+	}
+	samples, err := Samples(client, testset)
+	if err != nil {
+		return missing, err
+	}
+
+	files := make(map[string][]byte)
+	for _, sample := range samples {
+		input, output := ExampleFiles(sample.Index)
+		files[input], files[output] = []byte(sample.Input), []byte(sample.Output)
+	}
+	style, ok, err := olympStyle(client)
+	if err != nil {
+		return missing, err
+	}
+	if ok {
+		files["olymp.sty"] = []byte(style)
+	} else {
+		missing = append(missing, "olymp.sty")
+	}
+	resources, err := client.ProblemStatementResources(map[string]string{})
+	if err != nil {
+		return missing, err
+	}
+	for _, resource := range resources {
+		if config.ReadResource == nil {
+			missing = append(missing, resource.Name)
+			continue
+		}
+		if files[resource.Name], err = config.ReadResource(resource.Name); err != nil {
+			return missing, err
+		}
+	}
+
+	languages := make([]string, 0, len(statements))
+	for language := range statements {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	for _, language := range languages {
+		problem := Problem{Info: info, Statement: statements[language], Samples: samples}
+		files["problem.tex"] = []byte(LaTeXDocument(language, problem))
+		if err = writeFiles(filepath.Join(dir, language), files); err != nil {
+			return missing, err
+		}
+	}
+	return missing, nil
+}
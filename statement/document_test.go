@@ -0,0 +1,40 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/variety-jones/polygon"
+)
+
+func TestProblemLaTeX(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     polygon.ProblemInfoObject
+		language string
+		want     string
+	}{
+		{
+			"escaped name",
+			polygon.ProblemInfoObject{TimeLimit: 1000, MemoryLimit: 256},
+			"english",
+			`\begin{problem}{50\% of A\_1 \& B\#\{2\}}{standard input}{standard output}{1 second}{256 megabytes}{}`,
+		},
+		{
+			"escaped files",
+			polygon.ProblemInfoObject{InputFile: "in_1.txt", OutputFile: "stdout", TimeLimit: 2500, MemoryLimit: 64},
+			"russian",
+			`\begin{problem}{50\% of A\_1 \& B\#\{2\}}{in\_1.txt}{стандартный вывод}{2.5 секунды}{64 мегабайт}{}`,
+		},
+	}
+	for _, test := range tests {
+		problem := Problem{Info: test.info, Statement: polygon.StatementObject{Name: `50% of A_1 & B#{2}`, Legend: "$a_1$"}}
+		latex := problem.LaTeX(test.language)
+		if !strings.HasPrefix(latex, test.want+"\n") {
+			t.Errorf("%s: got\n%s\nwant it to start with\n%s", test.name, latex, test.want)
+		}
+		if !strings.Contains(latex, "\n$a_1$\n") {
+			t.Errorf("%s: the legend was changed in\n%s", test.name, latex)
+		}
+	}
+}
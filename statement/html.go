@@ -0,0 +1,158 @@
+package statement
+
+import (
+	"html"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+)
+
+// htmlStyles maps styles to their HTML elements
+var htmlStyles = map[string]string{
+	bold:      "b",
+	italic:    "i",
+	monospace: "code",
+	underline: "u",
+	strike:    "s",
+}
+
+// safeURL tells whether a link may be rendered: http(s), mailto, or a relative url
+func safeURL(url string) bool {
+	lower := strings.ToLower(strings.TrimSpace(url))
+	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "mailto:") {
+		return true
+	}
+	return !strings.Contains(lower, ":")
+}
+
+// renderHTML renders parsed nodes, text being escaped, so that the result only holds the markup
+// produced here. Math is kept for MathJax between \( \) and \[ \].
+func renderHTML(b *strings.Builder, nodes []node, options Options) {
+	for _, run := range paragraphs(nodes) {
+		if len(run) == 1 && run[0].isBlock() {
+			renderHTMLNode(b, run[0], options)
+			continue
+		}
+		b.WriteString("<p>")
+		renderHTMLInline(b, run, options)
+		b.WriteString("</p>\n")
+	}
+}
+
+// renderHTMLInline renders nodes without splitting them into paragraphs
+func renderHTMLInline(b *strings.Builder, nodes []node, options Options) {
+	for _, n := range nodes {
+		if n.kind == paragraphNode {
+			b.WriteString("<br>\n")
+			continue
+		}
+		renderHTMLNode(b, n, options)
+	}
+}
+
+// renderHTMLNode renders a node
+func renderHTMLNode(b *strings.Builder, n node, options Options) {
+	switch n.kind {
+	case textNode:
+		b.WriteString(html.EscapeString(n.text))
+	case mathNode:
+		if n.display {
+			b.WriteString("<div class=\"math\">\\[" + html.EscapeString(n.text) + "\\]</div>\n")
+		} else {
+			b.WriteString("<span class=\"math\">\\(" + html.EscapeString(n.text) + "\\)</span>")
+		}
+	case styleNode:
+		tag := htmlStyles[n.text]
+		b.WriteString("<" + tag + ">")
+		renderHTMLInline(b, n.children, options)
+		b.WriteString("</" + tag + ">")
+	case listNode:
+		tag := "ul"
+		if n.display {
+			tag = "ol"
+		}
+		b.WriteString("<" + tag + ">\n")
+		for _, item := range n.children {
+			b.WriteString("<li>")
+			renderHTMLInline(b, item.children, options)
+			b.WriteString("</li>\n")
+		}
+		b.WriteString("</" + tag + ">\n")
+	case centerNode:
+		b.WriteString("<div class=\"center\">\n")
+		renderHTML(b, n.children, options)
+		b.WriteString("</div>\n")
+	case verbatimNode:
+		b.WriteString("<pre>" + html.EscapeString(n.text) + "</pre>\n")
+	case epigraphNode:
+		b.WriteString("<div class=\"epigraph\">\n<div class=\"epigraph-text\">")
+		renderHTMLInline(b, n.children, options)
+		b.WriteString("</div>\n<div class=\"epigraph-source\">")
+		renderHTMLInline(b, n.extra, options)
+		b.WriteString("</div>\n</div>\n")
+	case imageNode:
+		src := options.imageURL(n.text)
+		if safeURL(src) {
+			b.WriteString("<img src=\"" + html.EscapeString(src) + "\" alt=\"" + html.EscapeString(n.text) + "\">")
+		}
+	case linkNode:
+		if !safeURL(n.text) {
+			renderHTMLInline(b, n.children, options)
+			return
+		}
+		b.WriteString("<a href=\"" + html.EscapeString(n.text) + "\">")
+		renderHTMLInline(b, n.children, options)
+		b.WriteString("</a>")
+	case lineBreakNode:
+		b.WriteString("<br>\n")
+	}
+}
+
+// LaTeXToHTML converts polygon LaTeX, such as a field of a StatementObject, to sanitized HTML
+func LaTeXToHTML(tex string, options Options) string {
+	var b strings.Builder
+	renderHTML(&b, parseLaTeX(tex), options)
+	return b.String()
+}
+
+// renderHTMLSamples renders the examples of a statement
+func renderHTMLSamples(b *strings.Builder, samples []Sample, titles Titles) {
+	b.WriteString("<div class=\"sample-tests\">\n")
+	b.WriteString("<div class=\"section-title\">" + html.EscapeString(titles.Examples) + "</div>\n")
+	for _, sample := range samples {
+		b.WriteString("<div class=\"sample-test\">\n")
+		b.WriteString("<div class=\"input\"><div class=\"title\">" + html.EscapeString(titles.Input) + "</div>")
+		b.WriteString("<pre>" + html.EscapeString(sample.Input) + "</pre></div>\n")
+		b.WriteString("<div class=\"output\"><div class=\"title\">" + html.EscapeString(titles.Output) + "</div>")
+		b.WriteString("<pre>" + html.EscapeString(sample.Output) + "</pre></div>\n")
+		b.WriteString("</div>\n")
+	}
+	b.WriteString("</div>\n")
+}
+
+// HTML renders a statement as an HTML fragment, laid out like codeforces, with its examples.
+// Math is left for MathJax between \( \) and \[ \], and images are linked through options.ImageURL.
+// The tutorial is not part of the statement, it can be rendered with LaTeXToHTML.
+func HTML(statement polygon.StatementObject, samples []Sample, options Options) string {
+	titles := options.titles()
+	var b strings.Builder
+	b.WriteString("<div class=\"problem-statement\">\n")
+	b.WriteString("<div class=\"title\">" + html.EscapeString(statement.Name) + "</div>\n")
+	b.WriteString("<div class=\"legend\">\n" + LaTeXToHTML(statement.Legend, options) + "</div>\n")
+
+	for _, section := range sections(statement, titles) {
+		if section.name == "notes" && len(samples) > 0 {
+			renderHTMLSamples(&b, samples, titles)
+			samples = nil
+		}
+		if strings.TrimSpace(section.content) == "" {
+			continue
+		}
+		b.WriteString("<div class=\"" + section.name + "\">\n")
+		b.WriteString("<div class=\"section-title\">" + html.EscapeString(section.title) + "</div>\n")
+		b.WriteString(LaTeXToHTML(section.content, options))
+		b.WriteString("</div>\n")
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
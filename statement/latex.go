@@ -0,0 +1,379 @@
+// Package statement renders polygon statements, written in the LaTeX subset of olymp.sty,
+// to HTML, Markdown and standalone LaTeX documents.
+//
+// Supported markup: paragraphs, \\, $...$, $$...$$, \(...\), \[...\], \textbf, \textit, \emph,
+// \texttt, \underline, \sout, itemize and enumerate lists, center, verbatim and lstlisting environments,
+// \epigraph, \includegraphics, \url, \href, escaped characters and typographic ligatures
+// (~, --, ---, <<, >> and TeX quotes). Unknown commands are dropped, their arguments being kept as text.
+package statement
+
+import (
+	"strings"
+)
+
+// nodeKind is the type of a node of a parsed statement
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	mathNode
+	styleNode
+	listNode
+	itemNode
+	centerNode
+	verbatimNode
+	epigraphNode
+	imageNode
+	linkNode
+	lineBreakNode
+	paragraphNode
+)
+
+// Styles of a styleNode
+const (
+	bold      = "bold"
+	italic    = "italic"
+	monospace = "monospace"
+	underline = "underline"
+	strike    = "strike"
+)
+
+// node is an element of a parsed statement
+//
+// text     : content of a text, math, verbatim, image (file name) or link (url) node, style of a style node
+//
+// display  : display math, or ordered list
+//
+// children : content of a style, center or link node, items of a list, text of an epigraph
+//
+// extra    : author of an epigraph
+type node struct {
+	kind     nodeKind
+	text     string
+	display  bool
+	children []node
+	extra    []node
+}
+
+// styleCommands maps commands to the style they apply to their argument
+var styleCommands = map[string]string{
+	"textbf":    bold,
+	"textit":    italic,
+	"emph":      italic,
+	"textsl":    italic,
+	"texttt":    monospace,
+	"underline": underline,
+	"sout":      strike,
+}
+
+// symbolCommands maps commands to the text they produce
+var symbolCommands = map[string]string{
+	"ldots":          "…",
+	"dots":           "…",
+	"%":              "%",
+	"$":              "$",
+	"&":              "&",
+	"#":              "#",
+	"_":              "_",
+	"{":              "{",
+	"}":              "}",
+	" ":              " ",
+	"-":              "",
+	"LaTeX":          "LaTeX",
+	"TeX":            "TeX",
+	"textbackslash":  "\\",
+	"textasciitilde": "~",
+	"quad":           " ",
+	"qquad":          " ",
+}
+
+// ligatures are replaced in text, longest first
+var ligatures = strings.NewReplacer("---", "—", "--", "–", "<<", "«", ">>", "»", "``", "“", "''", "”", "~", " ")
+
+// parser reads a LaTeX source
+type parser struct {
+	source string
+	pos    int
+}
+
+// parseLaTeX parses a statement
+func parseLaTeX(source string) []node {
+	p := &parser{source: source}
+	nodes, _ := p.parseNodes("")
+	return nodes
+}
+
+// rest returns the source which was not read yet
+func (p *parser) rest() string {
+	if p.pos >= len(p.source) {
+		return ""
+	}
+	return p.source[p.pos:]
+}
+
+// peek tells whether the source continues with prefix
+func (p *parser) peek(prefix string) bool {
+	return strings.HasPrefix(p.rest(), prefix)
+}
+
+// readUntil returns the source up to the delimiter, and skips the delimiter
+func (p *parser) readUntil(delimiter string) string {
+	end := strings.Index(p.rest(), delimiter)
+	if end < 0 {
+		text := p.rest()
+		p.pos = len(p.source)
+		return text
+	}
+	text := p.source[p.pos : p.pos+end]
+	p.pos += end + len(delimiter)
+	return text
+}
+
+// readCommandName reads the name of a command, after its backslash
+func (p *parser) readCommandName() string {
+	if p.pos > len(p.source) {
+		p.pos = len(p.source)
+	}
+	start := p.pos
+	for p.pos < len(p.source) && isLetter(p.source[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start && p.pos < len(p.source) {
+		p.pos++
+	}
+	return p.source[start:p.pos]
+}
+
+// isLetter tells whether c may be part of a command name
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// skipSpaces skips the spaces following a command
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.source) && (p.source[p.pos] == ' ' || p.source[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// readRaw reads a balanced argument {...} without parsing it, or "" if there is none
+func (p *parser) readRaw(open, close byte) (text string, ok bool) {
+	p.skipSpaces()
+	if p.pos >= len(p.source) || p.source[p.pos] != open {
+		return "", false
+	}
+	depth, start := 0, p.pos+1
+	for ; p.pos < len(p.source); p.pos++ {
+		switch p.source[p.pos] {
+		case '\\':
+			p.pos++
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				p.pos++
+				return p.source[start : p.pos-1], true
+			}
+		}
+	}
+	// Unterminated, possibly after a trailing backslash
+	p.pos = len(p.source)
+	return p.source[start:], true
+}
+
+// readArgument parses a balanced argument {...}
+func (p *parser) readArgument() []node {
+	text, _ := p.readRaw('{', '}')
+	return parseLaTeX(text)
+}
+
+// parseNodes parses the source until \end{environment} when environment is set, or the end of the source
+func (p *parser) parseNodes(environment string) (nodes []node, closed bool) {
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, node{kind: textNode, text: ligatures.Replace(text.String())})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.source) {
+		c := p.source[p.pos]
+		switch {
+		case c == '%':
+			p.readUntil("\n")
+		case c == '\n':
+			p.pos++
+			start := p.pos
+			p.skipSpaces()
+			if p.peek("\n") {
+				flush()
+				nodes = append(nodes, node{kind: paragraphNode})
+				for p.pos < len(p.source) && strings.ContainsRune(" \t\n", rune(p.source[p.pos])) {
+					p.pos++
+				}
+			} else {
+				p.pos = start
+				text.WriteByte(' ')
+			}
+		case c == '$':
+			flush()
+			if p.peek("$$") {
+				p.pos += 2
+				nodes = append(nodes, node{kind: mathNode, text: strings.TrimSpace(p.readUntil("$$")), display: true})
+			} else {
+				p.pos++
+				nodes = append(nodes, node{kind: mathNode, text: p.readUntil("$")})
+			}
+		case c == '{':
+			flush()
+			nodes = append(nodes, p.readArgument()...)
+		case c == '}':
+			p.pos++
+		case c == '\\':
+			p.pos++
+			name := p.readCommandName()
+			if name == "end" {
+				p.readRaw('{', '}')
+				flush()
+				return nodes, true
+			}
+			if symbol, ok := symbolCommands[name]; ok {
+				text.WriteString(symbol)
+				if isLetter(name[0]) {
+					p.skipSpaces()
+				}
+				continue
+			}
+			flush()
+			nodes = append(nodes, p.parseCommand(name)...)
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	flush()
+	return nodes, environment == ""
+}
+
+// parseCommand parses a command whose name was read
+func (p *parser) parseCommand(name string) []node {
+	if style, ok := styleCommands[name]; ok {
+		return []node{{kind: styleNode, text: style, children: p.readArgument()}}
+	}
+
+	switch name {
+	case "\\":
+		p.readRaw('[', ']')
+		return []node{{kind: lineBreakNode}}
+	case "(":
+		return []node{{kind: mathNode, text: p.readUntil("\\)")}}
+	case "[":
+		return []node{{kind: mathNode, text: strings.TrimSpace(p.readUntil("\\]")), display: true}}
+	case "item":
+		p.readRaw('[', ']')
+		p.skipSpaces()
+		return []node{{kind: itemNode}}
+	case "begin":
+		environment, _ := p.readRaw('{', '}')
+		return p.parseEnvironment(environment)
+	case "includegraphics":
+		p.readRaw('[', ']')
+		file, _ := p.readRaw('{', '}')
+		return []node{{kind: imageNode, text: strings.TrimSpace(file)}}
+	case "epigraph":
+		text := p.readArgument()
+		return []node{{kind: epigraphNode, children: text, extra: p.readArgument()}}
+	case "url":
+		url, _ := p.readRaw('{', '}')
+		return []node{{kind: linkNode, text: url, children: []node{{kind: textNode, text: url}}}}
+	case "href":
+		url, _ := p.readRaw('{', '}')
+		return []node{{kind: linkNode, text: url, children: p.readArgument()}}
+	}
+
+	// Unknown command: keep the text of its arguments
+	var nodes []node
+	p.readRaw('[', ']')
+	for {
+		start := p.pos
+		p.skipSpaces()
+		if !p.peek("{") {
+			p.pos = start
+			break
+		}
+		nodes = append(nodes, p.readArgument()...)
+	}
+	if len(nodes) == 0 {
+		p.skipSpaces()
+	}
+	return nodes
+}
+
+// parseEnvironment parses the content of \begin{environment}
+func (p *parser) parseEnvironment(environment string) []node {
+	switch environment {
+	case "verbatim", "lstlisting":
+		p.readRaw('[', ']')
+		text := p.readUntil("\\end{" + environment + "}")
+		return []node{{kind: verbatimNode, text: strings.Trim(text, "\n")}}
+	case "itemize", "enumerate":
+		children, _ := p.parseNodes(environment)
+		list := node{kind: listNode, display: environment == "enumerate"}
+		for _, child := range children {
+			if child.kind == itemNode {
+				list.children = append(list.children, node{kind: itemNode})
+			} else if len(list.children) > 0 {
+				item := &list.children[len(list.children)-1]
+				item.children = append(item.children, child)
+			}
+		}
+		return []node{list}
+	case "center":
+		children, _ := p.parseNodes(environment)
+		return []node{{kind: centerNode, children: children}}
+	}
+	children, _ := p.parseNodes(environment)
+	return children
+}
+
+// isBlock tells whether a node is rendered outside of paragraphs
+func (n node) isBlock() bool {
+	switch n.kind {
+	case listNode, centerNode, verbatimNode, epigraphNode, paragraphNode:
+		return true
+	case mathNode:
+		return n.display
+	}
+	return false
+}
+
+// paragraphs splits nodes into runs of inline nodes and block nodes, dropping paragraph breaks
+func paragraphs(nodes []node) (runs [][]node) {
+	var inline []node
+	flush := func() {
+		blank := true
+		for _, n := range inline {
+			if n.kind != textNode || strings.TrimSpace(n.text) != "" {
+				blank = false
+			}
+		}
+		if !blank {
+			runs = append(runs, inline)
+		}
+		inline = nil
+	}
+	for _, n := range nodes {
+		if !n.isBlock() {
+			inline = append(inline, n)
+			continue
+		}
+		flush()
+		if n.kind != paragraphNode {
+			runs = append(runs, []node{n})
+		}
+	}
+	flush()
+	return runs
+}
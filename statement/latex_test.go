@@ -0,0 +1,56 @@
+package statement
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLaTeX(t *testing.T) {
+	text := func(s string) node { return node{kind: textNode, text: s} }
+	tests := []struct {
+		name   string
+		source string
+		want   []node
+	}{
+		{"text", "Hello -- world", []node{text("Hello – world")}},
+		{"comment", "a % comment\nb", []node{text("a b")}},
+		{"paragraphs", "a\n\nb", []node{text("a"), {kind: paragraphNode}, text("b")}},
+		{"inline math", "$n \\le 10^9$", []node{{kind: mathNode, text: "n \\le 10^9"}}},
+		{"display math", "$$ x $$", []node{{kind: mathNode, text: "x", display: true}}},
+		{"style", "\\textbf{a}", []node{{kind: styleNode, text: bold, children: []node{text("a")}}}},
+		{"symbol", "\\ldots x", []node{text("…x")}},
+		{"unknown command", "\\foo[x]{a}{b} c", []node{text("a"), text("b"), text(" c")}},
+		{"image", "\\includegraphics[width=5cm]{pic.png}", []node{{kind: imageNode, text: "pic.png"}}},
+		{"link", "\\href{https://a.b}{here}", []node{{kind: linkNode, text: "https://a.b", children: []node{text("here")}}}},
+		{
+			"list",
+			"\\begin{itemize}\\item a \\item b\\end{itemize}",
+			[]node{{kind: listNode, children: []node{{kind: itemNode, children: []node{text("a ")}}, {kind: itemNode, children: []node{text("b")}}}}},
+		},
+		{"verbatim", "\\begin{verbatim}\n$x$\n\\end{verbatim}", []node{{kind: verbatimNode, text: "$x$"}}},
+		{"unterminated unknown command", "\\foo{a\\", []node{text("a")}},
+		{"unterminated argument", "\\textbf{a", []node{{kind: styleNode, text: bold, children: []node{text("a")}}}},
+		{"unterminated math", "$x", []node{{kind: mathNode, text: "x"}}},
+		{"trailing backslash", "a\\", []node{text("a")}},
+		{"unterminated environment", "\\begin{center}a", []node{{kind: centerNode, children: []node{text("a")}}}},
+	}
+	for _, test := range tests {
+		if got := parseLaTeX(test.source); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: parseLaTeX(%q) = %+v, want %+v", test.name, test.source, got, test.want)
+		}
+	}
+}
+
+func TestParseLaTeXPrefixes(t *testing.T) {
+	source := "\\begin{itemize}\\item \\textbf{a\\{b} $x$ \\foo[o]{c\\\\d} \\url{u} \\end{itemize}\\(y\\)\\[z\\]%c\n\\begin{verbatim}v\\end{verbatim}"
+	for end := 0; end <= len(source); end++ {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("parseLaTeX(%q) panicked: %v", source[:end], r)
+				}
+			}()
+			LaTeXToHTML(source[:end], Options{})
+		}()
+	}
+}
@@ -0,0 +1,140 @@
+package statement
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/variety-jones/polygon"
+)
+
+// markdownEscaper escapes the characters of text which markdown would interpret
+var markdownEscaper = strings.NewReplacer(
+	"\\", "\\\\", "*", "\\*", "_", "\\_", "`", "\\`", "[", "\\[", "]", "\\]",
+	"#", "\\#", "$", "\\$", "<", "&lt;", ">", "&gt;", "&", "&amp;",
+)
+
+// markdownStyles maps styles to their markdown delimiters, underline having none
+var markdownStyles = map[string]string{
+	bold:      "**",
+	italic:    "*",
+	monospace: "`",
+	strike:    "~~",
+}
+
+// renderMarkdown renders parsed nodes as paragraphs separated by blank lines
+func renderMarkdown(b *strings.Builder, nodes []node, options Options) {
+	for i, run := range paragraphs(nodes) {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if len(run) == 1 && run[0].isBlock() {
+			renderMarkdownNode(b, run[0], options)
+			continue
+		}
+		var paragraph strings.Builder
+		renderMarkdownInline(&paragraph, run, options)
+		b.WriteString(strings.TrimSpace(paragraph.String()) + "\n")
+	}
+}
+
+// renderMarkdownInline renders nodes without splitting them into paragraphs
+func renderMarkdownInline(b *strings.Builder, nodes []node, options Options) {
+	for _, n := range nodes {
+		if n.kind == paragraphNode {
+			b.WriteString("  \n")
+			continue
+		}
+		renderMarkdownNode(b, n, options)
+	}
+}
+
+// indent indents the lines following the first one
+func indent(text, prefix string) string {
+	return strings.ReplaceAll(strings.TrimRight(text, "\n"), "\n", "\n"+prefix)
+}
+
+// renderMarkdownNode renders a node
+func renderMarkdownNode(b *strings.Builder, n node, options Options) {
+	switch n.kind {
+	case textNode:
+		b.WriteString(markdownEscaper.Replace(n.text))
+	case mathNode:
+		if n.display {
+			b.WriteString("$$\n" + n.text + "\n$$\n")
+		} else {
+			b.WriteString("$" + n.text + "$")
+		}
+	case styleNode:
+		var content strings.Builder
+		renderMarkdownInline(&content, n.children, options)
+		delimiter := markdownStyles[n.text]
+		b.WriteString(delimiter + strings.TrimSpace(content.String()) + delimiter)
+	case listNode:
+		for i, item := range n.children {
+			marker := "- "
+			if n.display {
+				marker = strconv.Itoa(i+1) + ". "
+			}
+			var content strings.Builder
+			renderMarkdownInline(&content, item.children, options)
+			b.WriteString(marker + indent(strings.TrimSpace(content.String()), strings.Repeat(" ", len(marker))) + "\n")
+		}
+	case centerNode:
+		renderMarkdown(b, n.children, options)
+	case verbatimNode:
+		b.WriteString("```\n" + n.text + "\n```\n")
+	case epigraphNode:
+		var text, source strings.Builder
+		renderMarkdownInline(&text, n.children, options)
+		renderMarkdownInline(&source, n.extra, options)
+		b.WriteString("> " + indent(strings.TrimSpace(text.String()), "> ") + "\n>\n> — " + strings.TrimSpace(source.String()) + "\n")
+	case imageNode:
+		if src := options.imageURL(n.text); safeURL(src) {
+			b.WriteString("![" + markdownEscaper.Replace(n.text) + "](" + src + ")")
+		}
+	case linkNode:
+		var content strings.Builder
+		renderMarkdownInline(&content, n.children, options)
+		if !safeURL(n.text) {
+			b.WriteString(content.String())
+			return
+		}
+		b.WriteString("[" + content.String() + "](" + n.text + ")")
+	case lineBreakNode:
+		b.WriteString("  \n")
+	}
+}
+
+// LaTeXToMarkdown converts polygon LaTeX, such as a field of a StatementObject, to Markdown.
+// Math is kept between $ and $$, as understood by most markdown renderers.
+func LaTeXToMarkdown(tex string, options Options) string {
+	var b strings.Builder
+	renderMarkdown(&b, parseLaTeX(tex), options)
+	return b.String()
+}
+
+// Markdown renders a statement as a Markdown document, with its examples.
+// The tutorial is not part of the statement, it can be rendered with LaTeXToMarkdown.
+func Markdown(statement polygon.StatementObject, samples []Sample, options Options) string {
+	titles := options.titles()
+	var b strings.Builder
+	b.WriteString("# " + markdownEscaper.Replace(statement.Name) + "\n\n")
+	b.WriteString(LaTeXToMarkdown(statement.Legend, options))
+
+	for _, section := range sections(statement, titles) {
+		if section.name == "notes" && len(samples) > 0 {
+			b.WriteString("\n## " + markdownEscaper.Replace(titles.Examples) + "\n")
+			for _, sample := range samples {
+				b.WriteString("\n### " + markdownEscaper.Replace(titles.Input) + "\n\n```\n" + strings.TrimRight(sample.Input, "\n") + "\n```\n")
+				b.WriteString("\n### " + markdownEscaper.Replace(titles.Output) + "\n\n```\n" + strings.TrimRight(sample.Output, "\n") + "\n```\n")
+			}
+			samples = nil
+		}
+		if strings.TrimSpace(section.content) == "" {
+			continue
+		}
+		b.WriteString("\n## " + markdownEscaper.Replace(section.title) + "\n\n")
+		b.WriteString(LaTeXToMarkdown(section.content, options))
+	}
+	return b.String()
+}
@@ -0,0 +1,99 @@
+package statement
+
+import (
+	"strconv"
+
+	"github.com/variety-jones/polygon"
+)
+
+// Titles are the titles of the sections of a statement
+type Titles struct {
+	Input    string
+	Output   string
+	Scoring  string
+	Examples string
+	Notes    string
+}
+
+// EnglishTitles are the default titles
+var EnglishTitles = Titles{Input: "Input", Output: "Output", Scoring: "Scoring", Examples: "Examples", Notes: "Note"}
+
+// Options holds the settings of the renderers, all fields are optional
+//
+// ImageURL : returns the url of a file included by \includegraphics, defaults to the file name
+//
+// Titles   : titles of the sections, defaults to EnglishTitles
+type Options struct {
+	ImageURL func(name string) string
+	Titles   *Titles
+}
+
+// imageURL returns the url of an image
+func (options Options) imageURL(name string) string {
+	if options.ImageURL == nil {
+		return name
+	}
+	return options.ImageURL(name)
+}
+
+// titles returns the configured titles or the default ones
+func (options Options) titles() Titles {
+	if options.Titles == nil {
+		return EnglishTitles
+	}
+	return *options.Titles
+}
+
+// section is a titled part of a statement, name being its HTML class
+type section struct {
+	name    string
+	title   string
+	content string
+}
+
+// sections returns the sections of a statement following the legend, in order.
+// Examples go before the notes.
+func sections(statement polygon.StatementObject, titles Titles) []section {
+	return []section{
+		{"input-specification", titles.Input, statement.Input},
+		{"output-specification", titles.Output, statement.Output},
+		{"scoring", titles.Scoring, statement.Scoring},
+		{"notes", titles.Notes, statement.Notes},
+	}
+}
+
+// Sample is an example shown in a statement
+type Sample struct {
+	Index  int
+	Input  string
+	Output string
+}
+
+// Samples returns the examples of a statement: the tests of the testset used in statements,
+// with their input and output for statements when set, their input and answer otherwise.
+func Samples(client polygon.ProblemReader, testset string) (samples []Sample, err error) {
+	tests, err := client.ProblemTests(map[string]string{"testset": testset, "noInputs": "true"})
+	if err != nil {
+		return samples, err
+	}
+	for _, test := range tests {
+		if !test.UseInStatements {
+			continue
+		}
+
+		sample := Sample{Index: test.Index, Input: test.InputForStatement, Output: test.OutputForStatement}
+		parameters := map[string]string{"testset": testset, "testIndex": strconv.Itoa(test.Index)}
+		if sample.Input == "" {
+			if sample.Input, err = client.ProblemTestInput(parameters); err != nil {
+				return samples, err
+			}
+		}
+		if sample.Output == "" {
+			if sample.Output, err = client.ProblemTestAnswer(parameters); err != nil {
+				return samples, err
+			}
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
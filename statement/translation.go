@@ -0,0 +1,205 @@
+package statement
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/variety-jones/polygon"
+)
+
+// TranslationIssue is a difference between a translation and the source statement
+//
+// Language : language of the translation
+//
+// Section  : field of the statement, e.g. "input", or "" for the whole statement
+type TranslationIssue struct {
+	Language string
+	Section  string
+	Message  string
+}
+
+// String returns the issue as "language: section: message"
+func (issue TranslationIssue) String() string {
+	if issue.Section == "" {
+		return issue.Language + ": " + issue.Message
+	}
+	return issue.Language + ": " + issue.Section + ": " + issue.Message
+}
+
+// TranslationConfig holds the settings of CheckTranslations
+//
+// Source   : language the translations are compared to, defaults to "english"
+//
+// Modified : time of the last edit of each statement, optional. The polygon API does not
+// report it, it may come from a package or a local copy of the statements.
+type TranslationConfig struct {
+	Source   string
+	Modified map[string]time.Time
+}
+
+// numberPattern matches numeric constants
+var numberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// mathSpaces are removed from math before comparing it, along with braces, so that 10^9 matches 10^{9}
+var mathSpaces = strings.NewReplacer(" ", "", "\t", "", "\n", "", "\\,", "", "\\;", "", "\\!", "", "~", "", "{", "", "}", "")
+
+// translationSection is a field of a statement
+type translationSection struct {
+	name    string
+	content string
+}
+
+// translationSections returns the fields of a statement compared between languages
+func translationSections(statement polygon.StatementObject) []translationSection {
+	return []translationSection{
+		{"name", statement.Name},
+		{"legend", statement.Legend},
+		{"input", statement.Input},
+		{"output", statement.Output},
+		{"scoring", statement.Scoring},
+		{"notes", statement.Notes},
+		{"tutorial", statement.Tutorial},
+	}
+}
+
+// collect appends the math, numbers and images found in nodes
+func collect(nodes []node, math, numbers, images map[string]int) {
+	for _, n := range nodes {
+		switch n.kind {
+		case mathNode:
+			math[mathSpaces.Replace(n.text)]++
+			for _, number := range numberPattern.FindAllString(n.text, -1) {
+				numbers[number]++
+			}
+		case textNode:
+			for _, number := range numberPattern.FindAllString(n.text, -1) {
+				numbers[number]++
+			}
+		case imageNode:
+			images[n.text]++
+		}
+		collect(n.children, math, numbers, images)
+		collect(n.extra, math, numbers, images)
+	}
+}
+
+// difference returns the keys of a missing from b and of b missing from a, sorted
+func difference(a, b map[string]int) (missing, extra []string) {
+	for key := range a {
+		if _, ok := b[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(extra)
+	return missing, extra
+}
+
+// contents holds what is compared in a section
+type contents struct {
+	math, numbers, images map[string]int
+}
+
+// sectionContents parses a section
+func sectionContents(tex string) contents {
+	c := contents{make(map[string]int), make(map[string]int), make(map[string]int)}
+	collect(parseLaTeX(tex), c.math, c.numbers, c.images)
+	return c
+}
+
+// CheckTranslations compares the statements of a problem to the statement in the source language:
+// the same sections must be filled, input and output specifications must hold the same math
+// expressions and numeric constants, and the statements must include the same images.
+// Translations last edited before the source statement are reported as outdated.
+func CheckTranslations(statements map[string]polygon.StatementObject, config TranslationConfig) (issues []TranslationIssue) {
+	source := config.Source
+	if source == "" {
+		source = "english"
+	}
+	original, ok := statements[source]
+	if !ok {
+		return []TranslationIssue{{Language: source, Message: "no statement in the source language"}}
+	}
+
+	languages := make([]string, 0, len(statements))
+	for language := range statements {
+		if language != source {
+			languages = append(languages, language)
+		}
+	}
+	sort.Strings(languages)
+
+	originalSections := translationSections(original)
+	for _, language := range languages {
+		report := func(section, format string, args ...interface{}) {
+			issues = append(issues, TranslationIssue{Language: language, Section: section, Message: fmt.Sprintf(format, args...)})
+		}
+
+		originalImages, images := make(map[string]int), make(map[string]int)
+		for i, section := range translationSections(statements[language]) {
+			expected := originalSections[i]
+			if (strings.TrimSpace(expected.content) == "") != (strings.TrimSpace(section.content) == "") {
+				if strings.TrimSpace(section.content) == "" {
+					report(section.name, "missing, present in %s", source)
+				} else {
+					report(section.name, "not present in %s", source)
+				}
+			}
+
+			want, got := sectionContents(expected.content), sectionContents(section.content)
+			for name := range want.images {
+				originalImages[name]++
+			}
+			for name := range got.images {
+				images[name]++
+			}
+			if section.name != "input" && section.name != "output" {
+				continue
+			}
+			if missing, extra := difference(want.math, got.math); len(missing) > 0 || len(extra) > 0 {
+				report(section.name, "math differs: missing %s, extra %s", quoteAll(missing), quoteAll(extra))
+			}
+			if missing, extra := difference(want.numbers, got.numbers); len(missing) > 0 || len(extra) > 0 {
+				report(section.name, "numbers differ: missing %s, extra %s", quoteAll(missing), quoteAll(extra))
+			}
+		}
+		if missing, extra := difference(originalImages, images); len(missing) > 0 || len(extra) > 0 {
+			report("", "images differ: missing %s, extra %s", quoteAll(missing), quoteAll(extra))
+		}
+
+		modified, ok := config.Modified[language]
+		if sourceModified, sourceOk := config.Modified[source]; ok && sourceOk && modified.Before(sourceModified) {
+			report("", "outdated, last edited %s before %s", sourceModified.Sub(modified).Round(time.Second), source)
+		}
+	}
+	return issues
+}
+
+// quoteAll quotes values for a message, "none" if there are none
+func quoteAll(values []string) string {
+	if len(values) == 0 {
+		return "none"
+	}
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// CheckProblemTranslations compares the statements of a problem, see CheckTranslations
+func CheckProblemTranslations(client polygon.ProblemReader, config TranslationConfig) ([]TranslationIssue, error) {
+	statements, err := client.ProblemStatements(map[string]string{})
+	if err != nil {
+		return nil, err
+	}
+	return CheckTranslations(statements, config), nil
+}
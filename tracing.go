@@ -0,0 +1,50 @@
+package polygon
+
+import "context"
+
+// Tracer starts spans for API calls.
+// It mirrors the shape of OpenTelemetry's tracer, so that an adapter around
+// any tracing library can be plugged in without this package depending on it.
+//
+// Start receives the context of the call, holding the span of the caller if any,
+// and returns the context of the new span.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span represents a single traced operation.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// TracingInterceptor returns an Interceptor that wraps every call in a span
+// named after the API method, e.g. "polygon problem.info".
+//
+// The span is a child of the span of Request.Context, if any, and its context is passed
+// to the rest of the chain. It carries the attributes polygon.method, polygon.problem_id
+// and http.status_code.
+func TracingInterceptor(tracer Tracer) Interceptor {
+	return func(request *Request, next Handler) (response *Response, err error) {
+		ctx, span := tracer.Start(request.Context, "polygon "+request.Method)
+		defer span.End()
+		traced := *request
+		traced.Context = ctx
+		request = &traced
+
+		span.SetAttribute("polygon.method", request.Method)
+		if request.ProblemId != "" {
+			span.SetAttribute("polygon.problem_id", request.ProblemId)
+		}
+
+		response, err = next(request)
+		if response != nil {
+			span.SetAttribute("http.status_code", response.StatusCode)
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+		return response, err
+	}
+}
@@ -0,0 +1,64 @@
+package polygon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// recordingSpan keeps what is recorded on it
+type recordingSpan struct {
+	name       string
+	parent     interface{}
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (span *recordingSpan) SetAttribute(key string, value interface{}) { span.attributes[key] = value }
+func (span *recordingSpan) RecordError(err error)                      { span.err = err }
+func (span *recordingSpan) End()                                       { span.ended = true }
+
+// recordingTracer starts recordingSpans, storing the span in the returned context
+type recordingTracer struct {
+	spans []*recordingSpan
+}
+
+func (tracer *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{name: name, parent: ctx.Value(contextKey("span")), attributes: make(map[string]interface{})}
+	tracer.spans = append(tracer.spans, span)
+	return context.WithValue(ctx, contextKey("span"), span), span
+}
+
+func TestTracingInterceptor(t *testing.T) {
+	tracer := &recordingTracer{}
+	tracing := TracingInterceptor(tracer)
+	caller := &recordingSpan{}
+	request := &Request{
+		Method:    problemInfoEp,
+		ProblemId: "7",
+		Context:   context.WithValue(context.Background(), contextKey("span"), caller),
+	}
+
+	var inner context.Context
+	failure := errors.New("refused")
+	_, err := tracing(request, func(request *Request) (*Response, error) {
+		inner = request.Context
+		return &Response{StatusCode: http.StatusBadGateway}, failure
+	})
+	if err != failure || len(tracer.spans) != 1 {
+		t.Fatalf("got error %v and %d spans", err, len(tracer.spans))
+	}
+
+	span := tracer.spans[0]
+	if span.name != "polygon problem.info" || !span.ended || span.err != failure || span.parent != caller {
+		t.Errorf("got span %+v, want an ended child of the caller span named polygon problem.info with the error", span)
+	}
+	if span.attributes["polygon.problem_id"] != "7" || span.attributes["http.status_code"] != http.StatusBadGateway {
+		t.Errorf("got attributes %v", span.attributes)
+	}
+	if inner.Value(contextKey("span")) != span || request.Context.Value(contextKey("span")) != caller {
+		t.Error("the span context was not passed to the rest of the chain on a copy of the request")
+	}
+}
@@ -0,0 +1,222 @@
+package polygon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UploadConfig holds the settings of UploadTests
+//
+// Testset   : testset receiving the tests, defaults to "tests"
+//
+// Workers   : number of concurrent uploads, defaults to 4
+//
+// Interval  : minimum delay between the start of two uploads, 0 for none
+//
+// Overwrite : replace existing tests, otherwise checkExisting is set and existing tests are reported as failed
+type UploadConfig struct {
+	Testset   string
+	Workers   int
+	Interval  time.Duration
+	Overwrite bool
+}
+
+// TestFile is a test read from a directory by ReadTestFiles
+//
+// Path            : file holding the input
+//
+// Group, Points, Description, UseInStatements : metadata, empty when not set,
+// UseInStatements being "true" or "false"
+type TestFile struct {
+	Index           int
+	Path            string
+	Group           string
+	Points          string
+	Description     string
+	UseInStatements string
+}
+
+// UploadResult is the outcome of the upload of a test, Err being nil on success
+type UploadResult struct {
+	Index int
+	Path  string
+	Err   error
+}
+
+// testFilePattern matches the input files of tests, such as 01.in
+var testFilePattern = regexp.MustCompile(`^(\d+)\.in$`)
+
+// parseTestMetadata reads a metadata file: "key: value" lines, keys being group, points,
+// description and use-in-statements, and lines starting with # being comments
+func parseTestMetadata(test *TestFile, content string) error {
+	for number, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		colon := strings.Index(line, ":")
+		if colon < 0 {
+			return fmt.Errorf("line %d: expected \"key: value\"", number+1)
+		}
+		key, value := strings.TrimSpace(line[:colon]), strings.TrimSpace(line[colon+1:])
+		switch key {
+		case "group":
+			test.Group = value
+		case "points":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("line %d: invalid points %q", number+1, value)
+			}
+			test.Points = value
+		case "description":
+			test.Description = value
+		case "use-in-statements":
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid boolean %q", number+1, value)
+			}
+			test.UseInStatements = strconv.FormatBool(parsed)
+		default:
+			return fmt.Errorf("line %d: unknown key %q", number+1, key)
+		}
+	}
+	return nil
+}
+
+// ReadTestFiles lists the tests of a directory: files named NN.in, the number being the index
+// of the test, each one with an optional NN.meta file such as
+//
+//	group: 1
+//	points: 10
+//	description: maximal tree
+//	use-in-statements: false
+func ReadTestFiles(dir string) (tests []TestFile, err error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return tests, err
+	}
+	for _, file := range files {
+		match := testFilePattern.FindStringSubmatch(file.Name())
+		if file.IsDir() || match == nil {
+			continue
+		}
+		index, _ := strconv.Atoi(match[1])
+		test := TestFile{Index: index, Path: filepath.Join(dir, file.Name())}
+
+		metadata, err := ioutil.ReadFile(filepath.Join(dir, match[1]+".meta"))
+		if err != nil && !os.IsNotExist(err) {
+			return tests, err
+		}
+		if err == nil {
+			if err = parseTestMetadata(&test, string(metadata)); err != nil {
+				return tests, fmt.Errorf("polygon: %s.meta: %v", match[1], err)
+			}
+		}
+		tests = append(tests, test)
+	}
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Index < tests[j].Index })
+	for i := 1; i < len(tests); i++ {
+		if tests[i].Index == tests[i-1].Index {
+			return tests, fmt.Errorf("polygon: %s and %s are both test %d", tests[i-1].Path, tests[i].Path, tests[i].Index)
+		}
+	}
+	return tests, nil
+}
+
+// uploadTest saves a test without its group
+func uploadTest(client ProblemWriter, test TestFile, config UploadConfig) error {
+	input, err := ioutil.ReadFile(test.Path)
+	if err != nil {
+		return err
+	}
+	parameters := map[string]string{
+		"testset":       config.Testset,
+		"testIndex":     strconv.Itoa(test.Index),
+		"testInput":     string(input),
+		"checkExisting": strconv.FormatBool(!config.Overwrite),
+	}
+	for name, value := range map[string]string{
+		"testPoints":          test.Points,
+		"testDescription":     test.Description,
+		"testUseInStatements": test.UseInStatements,
+	} {
+		if value != "" {
+			parameters[name] = value
+		}
+	}
+	return client.ProblemSaveTest(parameters)
+}
+
+// UploadTests uploads the tests read by ReadTestFiles from dir with a pool of workers,
+// then sets the group of the uploaded tests with one ProblemSetTestGroup call per group.
+// A test whose upload or group assignment failed has its error in its result;
+// the returned error is only set when dir cannot be read.
+func UploadTests(client ProblemWriter, dir string, config UploadConfig) (results []UploadResult, err error) {
+	if config.Testset == "" {
+		config.Testset = "tests"
+	}
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	tests, err := ReadTestFiles(dir)
+	if err != nil {
+		return results, err
+	}
+
+	results = make([]UploadResult, len(tests))
+	limiter := &pacer{interval: config.Interval}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < config.Workers; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				time.Sleep(limiter.reserve())
+				results[i] = UploadResult{Index: tests[i].Index, Path: tests[i].Path, Err: uploadTest(client, tests[i], config)}
+			}
+		}()
+	}
+	for i := range tests {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	groups := make(map[string][]int)
+	for i, test := range tests {
+		if test.Group != "" && results[i].Err == nil {
+			groups[test.Group] = append(groups[test.Group], i)
+		}
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return LessGroup(names[i], names[j]) })
+	for _, name := range names {
+		indices := make([]string, len(groups[name]))
+		for j, i := range groups[name] {
+			indices[j] = strconv.Itoa(tests[i].Index)
+		}
+		time.Sleep(limiter.reserve())
+		err := client.ProblemSetTestGroup(map[string]string{
+			"testset":     config.Testset,
+			"testGroup":   name,
+			"testIndices": strings.Join(indices, ","),
+		})
+		if err != nil {
+			for _, i := range groups[name] {
+				results[i].Err = fmt.Errorf("polygon: setting group %s: %w", name, err)
+			}
+		}
+	}
+	return results, nil
+}
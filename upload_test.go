@@ -0,0 +1,149 @@
+package polygon_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/variety-jones/polygon"
+	"github.com/variety-jones/polygon/polygonmock"
+)
+
+// writeTestFiles writes files, by name, to a temporary directory
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadTestFiles(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{
+		"01.in":   "1",
+		"02.in":   "2",
+		"02.meta": "# sample\ngroup: 0\npoints: 2.5\ndescription: small\nuse-in-statements: 1\n",
+		"10.in":   "10",
+		"10.ans":  "ignored",
+		"notes":   "ignored",
+	})
+	tests, err := polygon.ReadTestFiles(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []polygon.TestFile{
+		{Index: 1, Path: filepath.Join(dir, "01.in")},
+		{Index: 2, Path: filepath.Join(dir, "02.in"), Group: "0", Points: "2.5", Description: "small", UseInStatements: "true"},
+		{Index: 10, Path: filepath.Join(dir, "10.in")},
+	}
+	if !reflect.DeepEqual(tests, want) {
+		t.Errorf("got %+v, want %+v", tests, want)
+	}
+
+	for name, content := range map[string]string{"unknown key": "color: red", "invalid boolean": "use-in-statements: maybe", "invalid points": "points: ten"} {
+		dir := writeTestFiles(t, map[string]string{"01.in": "1", "01.meta": content})
+		if _, err := polygon.ReadTestFiles(dir); err == nil {
+			t.Errorf("%s: metadata %q was accepted", name, content)
+		}
+	}
+
+	dir = writeTestFiles(t, map[string]string{"01.in": "1"})
+	if err = os.Mkdir(filepath.Join(dir, "01.meta"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = polygon.ReadTestFiles(dir); err == nil {
+		t.Error("an unreadable metadata file was ignored")
+	}
+}
+
+func TestUploadTestsCheckExisting(t *testing.T) {
+	dir := writeTestFiles(t, map[string]string{"01.in": "1", "02.in": "2"})
+	for _, overwrite := range []bool{false, true} {
+		client := &polygonmock.Client{}
+		results, err := polygon.UploadTests(client, dir, polygon.UploadConfig{Overwrite: overwrite})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				t.Errorf("test %d: %v", result.Index, result.Err)
+			}
+		}
+		calls := client.Calls()
+		if len(calls) != 2 {
+			t.Fatalf("got calls %+v, want two tests saved", calls)
+		}
+		for _, call := range calls {
+			if call.Method != "ProblemSaveTest" || call.Parameters["testset"] != "tests" ||
+				call.Parameters["checkExisting"] != strconv.FormatBool(!overwrite) {
+				t.Errorf("overwrite %v: got call %+v, want checkExisting %v", overwrite, call, !overwrite)
+			}
+		}
+	}
+}
+
+func TestUploadTestsGroups(t *testing.T) {
+	files := map[string]string{}
+	groups := map[int]string{1: "1", 2: "10", 3: "1", 4: "2", 5: "2", 6: ""}
+	for index, group := range groups {
+		name := strconv.Itoa(index)
+		files["0"+name+".in"] = name
+		if group != "" {
+			files["0"+name+".meta"] = "group: " + group
+		}
+	}
+	dir := writeTestFiles(t, files)
+
+	failure := errors.New("no such group")
+	var set []map[string]string
+	client := &polygonmock.Client{
+		ProblemSaveTestFunc: func(parameters map[string]string) error {
+			if parameters["testIndex"] == "5" {
+				return errors.New("test exists")
+			}
+			return nil
+		},
+		ProblemSetTestGroupFunc: func(parameters map[string]string) error {
+			set = append(set, parameters)
+			if parameters["testGroup"] == "10" {
+				return failure
+			}
+			return nil
+		},
+	}
+	results, err := polygon.UploadTests(client, dir, polygon.UploadConfig{Testset: "pretests", Workers: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []map[string]string{
+		{"testset": "pretests", "testGroup": "1", "testIndices": "1,3"},
+		{"testset": "pretests", "testGroup": "2", "testIndices": "4"},
+		{"testset": "pretests", "testGroup": "10", "testIndices": "2"},
+	}
+	if !reflect.DeepEqual(set, want) {
+		t.Errorf("groups set with %v, want %v", set, want)
+	}
+	for _, result := range results {
+		switch result.Index {
+		case 2:
+			if !errors.Is(result.Err, failure) {
+				t.Errorf("test 2: got error %v, want the group error", result.Err)
+			}
+		case 5:
+			if result.Err == nil {
+				t.Error("test 5: the upload error was lost")
+			}
+		default:
+			if result.Err != nil {
+				t.Errorf("test %d: %v", result.Index, result.Err)
+			}
+		}
+	}
+}
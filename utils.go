@@ -1,10 +1,10 @@
 package polygon
 
 import (
+	"context"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
-	"errors"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
@@ -56,7 +56,7 @@ func (api *PolygonApi) prepareURL(parametersOriginal map[string]string, methodNa
 	parameters["apiKey"] = api.ApiKey
 
 	// Add "problemId" parameter only if it is a prob
-	if !(methodName == "problems.list" || methodName == "contest.problems") {
+	if isProblemMethod(methodName) {
 		parameters["problemId"] = api.ProblemId
 	}
 
@@ -95,27 +95,49 @@ func (api *PolygonApi) prepareURL(parametersOriginal map[string]string, methodNa
 	return requestURL
 }
 
-// processRequest makes API calls and reports if there was any error
+// processRequest makes API calls through the interceptor chain and reports if there was any error
 func (api *PolygonApi) processRequest(parameters map[string]string, methodName string) (body []byte, err error) {
-	URL := api.prepareURL(parameters, methodName)
-	resp, err := http.Get(URL)
+	request := &Request{Method: methodName, Parameters: parameters, Context: api.ctx}
+	if request.Context == nil {
+		request.Context = context.Background()
+	}
+	if isProblemMethod(methodName) {
+		request.ProblemId = api.ProblemId
+	}
+
+	response, err := api.handler()(request)
+	if response != nil {
+		body = response.Body
+	}
+	return body, err
+}
+
+// sendRequest performs the actual HTTP call to polygon
+func (api *PolygonApi) sendRequest(request *Request) (response *Response, err error) {
+	URL := api.prepareURL(request.Parameters, request.Method)
+	httpRequest, err := http.NewRequestWithContext(request.Context, http.MethodGet, URL, nil)
+	if err != nil {
+		return response, err
+	}
+	resp, err := http.DefaultClient.Do(httpRequest)
 	if err != nil {
-		return body, err
+		return response, err
 	}
 
 	defer resp.Body.Close()
-	body, err = ioutil.ReadAll(resp.Body)
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return body, err
+		return response, err
 	}
+	response = &Response{StatusCode: resp.StatusCode, Body: body}
 
 	// TODO : Revisit this section later
 	if resp.StatusCode != http.StatusOK {
 		mp := make(map[string]string)
 		json.Unmarshal(body, &mp)
-		return body, errors.New("the http request returned a FAILED status with comment: " + mp["comment"])
+		return response, &ApiError{Method: request.Method, StatusCode: resp.StatusCode, Comment: mp["comment"]}
 	}
-	return body, err
+	return response, err
 }
 
 // extractView is a utility function for all methods that return a view
@@ -148,3 +170,21 @@ func (api *PolygonApi) checkForErrors(parameters map[string]string, methodName s
 	_, err = api.processRequest(parameters, methodName)
 	return err
 }
+
+// canonicalParameters encodes the parameters as a query string with sorted keys
+func canonicalParameters(parameters map[string]string) string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	encoded := ""
+	for i, key := range keys {
+		if i > 0 {
+			encoded += "&"
+		}
+		encoded += url.QueryEscape(key) + "=" + url.QueryEscape(parameters[key])
+	}
+	return encoded
+}